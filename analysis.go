@@ -0,0 +1,268 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UnreachableFrom returns every node that cannot be reached from any of the
+// entry files by following import edges. These are candidates for dead code.
+func UnreachableFrom(project Project, entries []string) []string {
+	reachable := reachableFrom(project, entries)
+
+	unreachable := []string{}
+	for _, id := range sortedNodeIDs(project) {
+		if !reachable[id] {
+			unreachable = append(unreachable, id)
+		}
+	}
+	return unreachable
+}
+
+// reachableFrom performs a forward traversal over import edges from the entries
+func reachableFrom(project Project, entries []string) map[string]bool {
+	reachable := make(map[string]bool)
+	queue := []string{}
+
+	for _, entry := range entries {
+		if _, exists := project.NodesMap[entry]; exists && !reachable[entry] {
+			reachable[entry] = true
+			queue = append(queue, entry)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, importPath := range project.NodesMap[id].Imports {
+			if _, exists := project.NodesMap[importPath]; exists && !reachable[importPath] {
+				reachable[importPath] = true
+				queue = append(queue, importPath)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// defaultEntryPoints guesses the project's entry files from common conventions:
+// src/index, src/main, a root index, and Next.js pages/app routes
+func defaultEntryPoints(project Project) []string {
+	entries := []string{}
+
+	for _, id := range sortedNodeIDs(project) {
+		p := ConvertToUnixPath(id)
+		name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+		dir := path.Dir(p)
+
+		switch {
+		case (dir == "src" || dir == ".") && (name == "index" || name == "main"):
+			entries = append(entries, id)
+		case isNextRoute(p):
+			entries = append(entries, id)
+		}
+	}
+
+	return entries
+}
+
+// isNextRoute reports whether a path is a Next.js pages or app router route file
+func isNextRoute(p string) bool {
+	p = strings.TrimPrefix(p, "src/")
+	name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+
+	if strings.HasPrefix(p, "pages/") {
+		return !strings.HasPrefix(path.Base(p), "_") && !strings.HasPrefix(p, "pages/api/")
+	}
+	if strings.HasPrefix(p, "app/") {
+		return name == "page" || name == "layout" || name == "route"
+	}
+	return false
+}
+
+// computeClosures stores every node's transitive dependencies and dependents.
+// Traversals track visited nodes, so import cycles terminate.
+func computeClosures(project *Project) {
+	for id, node := range project.NodesMap {
+		node.TransitiveDeps = transitiveClosure(project, id, func(n ComponentNode) []string { return n.Imports })
+		node.TransitiveDependents = transitiveClosure(project, id, func(n ComponentNode) []string { return n.ImportedBy })
+		project.NodesMap[id] = node
+	}
+}
+
+// transitiveClosure returns all nodes reachable from start via the given neighbours,
+// excluding start itself
+func transitiveClosure(project *Project, start string, neighbours func(ComponentNode) []string) []string {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	closure := []string{}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, next := range neighbours(project.NodesMap[id]) {
+			if _, exists := project.NodesMap[next]; !exists || visited[next] {
+				continue
+			}
+			visited[next] = true
+			closure = append(closure, next)
+			queue = append(queue, next)
+		}
+	}
+
+	sort.Strings(closure)
+	return closure
+}
+
+// StronglyConnectedComponents returns the groups of mutually dependent nodes
+// (strongly connected components with more than one node) using Tarjan's algorithm
+func StronglyConnectedComponents(project Project) [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	components := [][]string{}
+
+	var strongConnect func(id string)
+	strongConnect = func(id string) {
+		indices[id] = index
+		lowlinks[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		imports := append([]string{}, project.NodesMap[id].Imports...)
+		sort.Strings(imports)
+		for _, next := range imports {
+			if _, exists := project.NodesMap[next]; !exists {
+				continue
+			}
+			if _, visited := indices[next]; !visited {
+				strongConnect(next)
+				lowlinks[id] = min(lowlinks[id], lowlinks[next])
+			} else if onStack[next] {
+				lowlinks[id] = min(lowlinks[id], indices[next])
+			}
+		}
+
+		// id is the root of a component: pop it off the stack
+		if lowlinks[id] == indices[id] {
+			component := []string{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			if len(component) > 1 {
+				sort.Strings(component)
+				components = append(components, component)
+			}
+		}
+	}
+
+	for _, id := range sortedNodeIDs(project) {
+		if _, visited := indices[id]; !visited {
+			strongConnect(id)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// markTestedNodes sets HasTest on every node imported by a test file and
+// returns the sorted component and util nodes that have no test importer
+func markTestedNodes(project *Project) []string {
+	untested := []string{}
+	for _, id := range sortedNodeIDs(*project) {
+		node := project.NodesMap[id]
+		node.HasTest = false
+		for _, importer := range node.ImportedBy {
+			if project.NodesMap[importer].Type == "test" {
+				node.HasTest = true
+				break
+			}
+		}
+		project.NodesMap[id] = node
+
+		if !node.HasTest && (node.Type == "component" || node.Type == "util") {
+			untested = append(untested, id)
+		}
+	}
+	return untested
+}
+
+// UndirectedEdges returns every import relationship between scanned nodes
+// once, as a pair with the smaller ID first, so A importing B and B importing
+// A collapse into one edge. Self-imports are skipped and pairs are sorted.
+func UndirectedEdges(project Project) [][2]string {
+	seen := make(map[[2]string]bool)
+	edges := [][2]string{}
+
+	for _, id := range sortedNodeIDs(project) {
+		for _, target := range project.NodesMap[id].Imports {
+			if _, exists := project.NodesMap[target]; !exists || target == id {
+				continue
+			}
+			pair := [2]string{id, target}
+			if target < id {
+				pair = [2]string{target, id}
+			}
+			if !seen[pair] {
+				seen[pair] = true
+				edges = append(edges, pair)
+			}
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+	return edges
+}
+
+// Cycle is a group of mutually dependent modules classified by reach. An
+// "intra" cycle stays within one directory and is usually benign; an "inter"
+// cycle crosses directory boundaries and is worth fixing first.
+type Cycle struct {
+	Nodes []string `json:"nodes"`
+	Scope string   `json:"scope"` // intra or inter
+	Dir   string   `json:"dir"`   // deepest directory containing every node
+}
+
+// classifyCycles classifies each cluster by whether its nodes share a directory
+func classifyCycles(clusters [][]string) []Cycle {
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	cycles := make([]Cycle, 0, len(clusters))
+	for _, cluster := range clusters {
+		cycle := Cycle{Nodes: cluster, Scope: "intra", Dir: filepath.Dir(cluster[0])}
+		for _, id := range cluster[1:] {
+			dir := filepath.Dir(id)
+			if dir != cycle.Dir {
+				cycle.Scope = "inter"
+			}
+			for !pathWithin(dir, cycle.Dir) {
+				cycle.Dir = filepath.Dir(cycle.Dir)
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
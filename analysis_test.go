@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestClassifyCycles(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/cart/Cart.tsx":       "import { CartItem } from './CartItem'\nexport const Cart = () => <CartItem />\n",
+		"src/cart/CartItem.tsx":   "import { Cart } from './Cart'\nexport const CartItem = () => <div />\n",
+		"src/features/a/Form.tsx": "import { validate } from '../../shared/validate'\nexport const Form = () => <form />\n",
+		"src/shared/validate.ts":  "import { Form } from '../features/a/Form'\nexport const validate = () => true\n",
+		"src/App.tsx":             "import { Cart } from './cart/Cart'\n",
+	}, ScanOptions{})
+
+	got := map[string]Cycle{}
+	for _, cycle := range project.Cycles {
+		nodes := append([]string{}, cycle.Nodes...)
+		sort.Strings(nodes)
+		got[filepath.ToSlash(nodes[0])] = cycle
+	}
+	tests := []struct {
+		first, scope, dir string
+		size              int
+	}{
+		{"src/cart/Cart.tsx", "intra", "src/cart", 2},
+		{"src/features/a/Form.tsx", "inter", "src", 2},
+	}
+	if len(project.Cycles) != len(tests) {
+		t.Fatalf("cycles = %+v, want %d", project.Cycles, len(tests))
+	}
+	for _, tt := range tests {
+		cycle, ok := got[tt.first]
+		if !ok {
+			t.Errorf("no cycle through %s among %+v", tt.first, project.Cycles)
+			continue
+		}
+		if cycle.Scope != tt.scope || cycle.Dir != filepath.FromSlash(tt.dir) || len(cycle.Nodes) != tt.size {
+			t.Errorf("cycle through %s = %+v, want %s in %s", tt.first, cycle, tt.scope, tt.dir)
+		}
+	}
+
+	if got := classifyCycles(nil); got != nil {
+		t.Errorf("classifyCycles(nil) = %+v, want nil", got)
+	}
+	if got := classifyCycles([][]string{{"a.js", "b.js"}}); !reflect.DeepEqual(got, []Cycle{{Nodes: []string{"a.js", "b.js"}, Scope: "intra", Dir: "."}}) {
+		t.Errorf("root cycle = %+v", got)
+	}
+}
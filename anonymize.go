@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// anonymizer assigns stable pseudonyms to path segments, component names and
+// symbols. Pseudonyms are numbered in sorted order so a run is reproducible.
+type anonymizer struct {
+	dirs    map[string]string
+	files   map[string]string
+	symbols map[string]string
+}
+
+// anonymizeProject replaces names and paths with pseudonyms while keeping the
+// graph structure and type classifications intact
+func anonymizeProject(project *Project) {
+	a := anonymizer{
+		dirs:    make(map[string]string),
+		files:   make(map[string]string),
+		symbols: make(map[string]string),
+	}
+
+	// Seed pseudonyms in a deterministic order before rewriting, collecting
+	// every path and symbol through a dry run of the remapper
+	paths, symbols := []string{}, []string{}
+	dryRun := *project
+	projectRemapper{
+		path:   func(p string) string { paths = append(paths, p); return p },
+		symbol: func(symbol string) string { symbols = append(symbols, symbol); return symbol },
+	}.apply(&dryRun)
+	sort.Strings(paths)
+	sort.Strings(symbols)
+	for _, p := range paths {
+		a.path(p)
+	}
+	for _, symbol := range symbols {
+		if symbol != "" {
+			a.symbol(symbol)
+		}
+	}
+
+	// Component names follow the sorted order of their original IDs
+	names := make(map[string]string)
+	for i, id := range sortedNodeIDs(*project) {
+		names[a.path(id)] = "Component" + pseudonymLetters(i)
+	}
+
+	projectRemapper{path: a.path, symbol: a.symbol}.apply(project)
+
+	for id, node := range project.NodesMap {
+		node.Name = names[id]
+		node.Owner = ""
+		project.NodesMap[id] = node
+	}
+	renameTree(project.Root.Children, names)
+
+	project.Root.Name = "project"
+	project.Root.Path = "project"
+}
+
+// renameTree applies component pseudonyms to file nodes and directory
+// pseudonyms to directory nodes in the tree, dropping Git owners
+func renameTree(children []ComponentNode, names map[string]string) {
+	for i := range children {
+		if name, ok := names[children[i].ID]; ok {
+			children[i].Name = name
+		} else {
+			children[i].Name = filepath.Base(children[i].Path)
+		}
+		children[i].Owner = ""
+		renameTree(children[i].Children, names)
+	}
+}
+
+// path rewrites every segment of a path, keeping "." and ".." as well as file
+// extensions and index file names so the shape of the tree stays readable
+func (a anonymizer) path(p string) string {
+	segments := strings.Split(filepath.ToSlash(p), "/")
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			continue
+		}
+		if i == len(segments)-1 && filepath.Ext(segment) != "" {
+			segments[i] = a.file(segment)
+		} else {
+			segments[i] = a.dir(segment)
+		}
+	}
+	return filepath.FromSlash(strings.Join(segments, "/"))
+}
+
+// dir returns the pseudonym of a directory name
+func (a anonymizer) dir(name string) string {
+	if pseudonym, ok := a.dirs[name]; ok {
+		return pseudonym
+	}
+	pseudonym := fmt.Sprintf("dir%d", len(a.dirs)+1)
+	a.dirs[name] = pseudonym
+	return pseudonym
+}
+
+// file returns the pseudonym of a file name, preserving its extension
+func (a anonymizer) file(name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "index" {
+		return name
+	}
+	if pseudonym, ok := a.files[base]; ok {
+		return pseudonym + ext
+	}
+	pseudonym := fmt.Sprintf("file%d", len(a.files)+1)
+	a.files[base] = pseudonym
+	return pseudonym + ext
+}
+
+// symbol returns the pseudonym of an imported or exported symbol name
+func (a anonymizer) symbol(name string) string {
+	if name == "default" || name == "*" {
+		return name
+	}
+	if pseudonym, ok := a.symbols[name]; ok {
+		return pseudonym
+	}
+	pseudonym := fmt.Sprintf("symbol%d", len(a.symbols)+1)
+	a.symbols[name] = pseudonym
+	return pseudonym
+}
+
+// pseudonymLetters converts 0, 1, ..., 25, 26 into A, B, ..., Z, AA
+func pseudonymLetters(i int) string {
+	letters := ""
+	for i >= 0 {
+		letters = string(rune('A'+i%26)) + letters
+		i = i/26 - 1
+	}
+	return letters
+}
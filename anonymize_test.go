@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeRedactsEverything(t *testing.T) {
+	base := writeFiles(t, map[string]string{
+		"acmeshop/tsconfig.json": `{"compilerOptions": {"baseUrl": ".", "paths": {
+			"@acmeloop/*": ["@acmeloop/*"],
+			"@acmeone/*": ["@acmetwo/*"],
+			"@acmetwo/*": ["@acmeone/*"]
+		}}}`,
+		"acmeshop/.reactviz.json":           `{"hide": ["acmeweb/acmeLegacy.tsx"]}`,
+		"acmeshop/acmeweb/CheckoutForm.tsx": "import { price } from '../../acmeshared/pricing'\nimport { secret } from './secretUtils'\nexport default function CheckoutForm() { return <form /> }\n",
+		"acmeshop/acmeweb/secretUtils.ts":   "export const secret = 42\n",
+		"acmeshop/acmeweb/acmeRouter.tsx": `import { lazy } from 'react'
+import { createBrowserRouter } from 'react-router-dom'
+import CheckoutForm from './CheckoutForm'
+
+const AcmeOrder = lazy(() => import('./AcmeOrder'))
+
+export const acmeRoutes = [
+  { path: '/acmecheckout/:orderId', component: lazy(() => import('./AcmeOrder')) },
+]
+
+export const router = createBrowserRouter([
+  { path: '/acmeadmin', element: <CheckoutForm />, children: [{ path: 'acmereports', element: <AcmeOrder /> }] },
+])
+
+export function loadAcmeMessages(lang) {
+  return import(` + "`./acmelocales/${lang}.json`" + `)
+}
+
+if (process.env.ACME_SECRET_FLAG === 'acmeon') {
+  require('./acmeDebug')
+}
+`,
+		"acmeshop/acmeweb/AcmeOrder.tsx":       "export default function AcmeOrder() { return <main /> }\n",
+		"acmeshop/acmeweb/acmeDebug.ts":        "export const acmeDebug = true\n",
+		"acmeshop/acmeweb/acmelocales/en.json": "{}\n",
+		"acmeshared/pricing.ts":                "export const price = 1\n",
+	})
+	root := filepath.Join(base, "acmeshop")
+
+	project := scanDir(t, root, ScanOptions{Anonymize: true, TreeRoot: "acmeweb"})
+	if len(project.ScanWarnings) < 4 {
+		t.Fatalf("warnings = %q, want the alias, out-of-root and sidecar warnings", project.ScanWarnings)
+	}
+
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := strings.ToLower(string(data))
+	for _, original := range append(strings.Split(filepath.ToSlash(base), "/"), "acme", "checkout", "secret", "pricing", "legacy", "orderid", "lang") {
+		if len(original) > 3 && strings.Contains(output, strings.ToLower(original)) {
+			t.Errorf("anonymized output contains %q: %s", original, data)
+		}
+	}
+
+	// The fixture must keep filling every field that holds source names
+	for _, field := range []string{`"treeRoot":"`, `"path":"/`, `"route":"/`, `"pattern":"./`, `"params":["`, `"condition":"process.env.`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("anonymized output has no %s field", field)
+		}
+	}
+}
@@ -4,12 +4,20 @@ import "github.com/wailsapp/wails/v2/pkg/runtime"
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
+
+	// The project last returned by ScanProject, which CollapseSubtree folds
+	// without rescanning
+	mu          sync.Mutex
+	lastDir     string
+	lastProject *Project
 }
 
 // NewApp creates a new App application struct
@@ -30,7 +38,141 @@ func (a *App) Greet(name string) string {
 
 // ScanProject scans a React project directory and returns visualization data
 func (a *App) ScanProject(dir string) (string, error) {
-	return GetProjectJSON(dir)
+	project, err := ScanProject(dir)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.lastDir, a.lastProject = dir, &project
+	a.mu.Unlock()
+
+	return projectJSON(dir, project, "", true)
+}
+
+// ScanAnonymized scans a React project and returns visualization data with
+// names and paths replaced by pseudonyms
+func (a *App) ScanAnonymized(dir string) (string, error) {
+	opts := DefaultScanOptions()
+	opts.Anonymize = true
+	return GetProjectJSONWithOptions(dir, opts)
+}
+
+// ScanArchive scans a zipped React project and returns visualization data
+func (a *App) ScanArchive(zipPath string) (string, error) {
+	return GetArchiveJSON(zipPath)
+}
+
+// ValidateConfig checks the project's import aliases against the filesystem
+// and returns the problems found as JSON
+func (a *App) ValidateConfig(dir string) (string, error) {
+	config, err := ReadProjectConfig(dir)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData, err := json.Marshal(ValidateAliases(dir, config))
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// LintProject scans a project and returns its lint findings as JSON
+func (a *App) LintProject(dir string) (string, error) {
+	project, err := ScanProject(dir)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData, err := json.MarshalIndent(LintProject(project, DefaultLintThresholds()), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// DetectEntryPoints scans a project and returns its detected entry files as JSON
+func (a *App) DetectEntryPoints(dir string) (string, error) {
+	project, err := ScanProject(dir)
+	if err != nil {
+		return "", err
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	// Without explicit entries, the scan records the detected ones
+	jsonData, err := json.Marshal(project.EntryPoints)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// ReduxGraph scans a project and returns its Redux slices, stores and
+// consumers as project JSON
+func (a *App) ReduxGraph(dir string) (string, error) {
+	project, err := ScanProject(dir)
+	if err != nil {
+		return "", err
+	}
+	project = ReduxGraph(project)
+	ConvertProjectPathsToUnix(&project)
+
+	jsonData, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// UsageRanking scans a project and returns how often each component is
+// imported and rendered as JSON, most used first
+func (a *App) UsageRanking(dir string) (string, error) {
+	project, err := ScanProject(dir)
+	if err != nil {
+		return "", err
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	jsonData, err := json.MarshalIndent(project.UsageRanking, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// CollapseSubtree folds each of the given directories or nodes of a project
+// into a single node and returns the resulting project JSON. It reuses the
+// project from the last ScanProject of dir; expanding a subtree again means
+// calling it without that path.
+func (a *App) CollapseSubtree(dir string, paths []string) (string, error) {
+	a.mu.Lock()
+	if a.lastProject == nil || a.lastDir != dir {
+		project, err := ScanProject(dir)
+		if err != nil {
+			a.mu.Unlock()
+			return "", err
+		}
+		a.lastDir, a.lastProject = dir, &project
+	}
+	project := *a.lastProject
+	a.mu.Unlock()
+
+	for _, path := range paths {
+		project = CollapseSubtree(project, path)
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	jsonData, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
 }
 
 // SelectDirectory opens a directory selection dialog
@@ -51,3 +193,44 @@ func (a *App) SelectDirectory() (string, error) {
 
 	return selectedDirectory, nil
 }
+
+// SelectTwoDirectories asks for a base and a comparison project directory.
+// An empty result is returned if either dialog is cancelled.
+func (a *App) SelectTwoDirectories() ([]string, error) {
+	baseDirectory, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Base Project Directory",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open directory dialog: %w", err)
+	}
+	if baseDirectory == "" {
+		return []string{}, nil
+	}
+
+	compareDirectory, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Project Directory to Compare",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open directory dialog: %w", err)
+	}
+	if compareDirectory == "" {
+		return []string{}, nil
+	}
+
+	return []string{baseDirectory, compareDirectory}, nil
+}
+
+// ScanAndDiff scans two project directories and returns their diff as JSON
+func (a *App) ScanAndDiff(dirA, dirB string) (string, error) {
+	diff, err := ScanAndDiff(dirA, dirB)
+	if err != nil {
+		return "", err
+	}
+
+	jsonData, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
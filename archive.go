@@ -0,0 +1,148 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Extraction limits that keep a zip bomb from filling the disk. Source files
+// are small, so legitimate projects stay far below them.
+const (
+	maxArchiveFileSize  = 16 << 20  // bytes extracted from a single entry
+	maxArchiveTotalSize = 512 << 20 // bytes extracted from the whole archive
+)
+
+// ScanArchive scans a zipped React project. The archive is extracted to a
+// temporary directory which is removed once the scan completes.
+func ScanArchive(zipPath string) (Project, error) {
+	tempDir, err := os.MkdirTemp("", "reactviz-archive-")
+	if err != nil {
+		return Project{}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := extractArchive(zipPath, tempDir); err != nil {
+		return Project{}, err
+	}
+
+	// Archives usually wrap the project in a single top-level directory
+	rootDir := tempDir
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return Project{}, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		rootDir = filepath.Join(tempDir, entries[0].Name())
+	}
+
+	// Archive contents are untrusted, so no single file may stall the scan
+	opts := DefaultScanOptions()
+	opts.ParseTimeout = archiveParseTimeout
+	project, err := ScanProjectWithOptions(rootDir, opts)
+	if err != nil {
+		return project, err
+	}
+
+	// Point the root at the archive rather than the temporary directory
+	project.Root.Name = strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))
+	project.Root.Path = zipPath
+
+	return project, nil
+}
+
+// GetArchiveJSON returns the scanned archive as JSON and saves it to disk
+func GetArchiveJSON(zipPath string) (string, error) {
+	project, err := ScanArchive(zipPath)
+	if err != nil {
+		return "", err
+	}
+
+	return projectJSON(zipPath, project, "", true)
+}
+
+// extractArchive extracts the React-relevant entries of a zip file into targetDir,
+// honouring the same directory skip rules as a regular scan. It fails once an
+// entry or the archive as a whole exceeds the extraction limits.
+func extractArchive(zipPath, targetDir string) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer reader.Close()
+
+	remaining := int64(maxArchiveTotalSize)
+	for _, file := range reader.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+
+		// Refuse entries that would escape the target directory
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("archive entry %q escapes the extraction directory", file.Name)
+		}
+
+		if isSkippedArchivePath(name) {
+			continue
+		}
+
+		destPath := filepath.Join(targetDir, filepath.FromSlash(name))
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(destPath, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		written, err := extractArchiveFile(file, destPath, min(maxArchiveFileSize, remaining))
+		if err != nil {
+			return err
+		}
+		remaining -= written
+	}
+
+	return nil
+}
+
+// isSkippedArchivePath reports whether any directory along an entry's path is skipped
+func isSkippedArchivePath(name string) bool {
+	dirs := strings.Split(path.Dir(name), "/")
+	for _, dir := range dirs {
+		if dir != "." && isSkippedDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchiveFile writes a single archive entry to destPath, refusing to
+// write more than limit bytes. It returns the number of bytes written.
+func extractArchiveFile(file *zip.File, destPath string, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return 0, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	// The sizes in the zip header can't be trusted, so count what is copied
+	written, err := io.CopyN(dst, src, limit+1)
+	if written > limit {
+		return written, fmt.Errorf("archive entry %q exceeds the extraction limit of %d bytes", file.Name, limit)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return written, err
+}
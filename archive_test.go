@@ -0,0 +1,71 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip archive of the given entries and returns its path
+func writeZip(t *testing.T, entries map[string][]byte) string {
+	t.Helper()
+	zipPath := filepath.Join(t.TempDir(), "project.zip")
+	file, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, content := range entries {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return zipPath
+}
+
+func TestScanArchive(t *testing.T) {
+	zipPath := writeZip(t, map[string][]byte{
+		"my-app/src/App.jsx":               []byte("import Button from './Button'\nexport default function App() { return <Button /> }\n"),
+		"my-app/src/Button.jsx":            []byte("export default function Button() { return <button /> }\n"),
+		"my-app/node_modules/lib/index.js": []byte("module.exports = {}\n"),
+	})
+
+	project, err := ScanArchive(zipPath)
+	if err != nil {
+		t.Fatalf("ScanArchive: %v", err)
+	}
+	if project.Root.Name != "project" {
+		t.Errorf("root name = %q, want project", project.Root.Name)
+	}
+	if _, ok := edgeTo(t, project, "src/App.jsx", "src/Button.jsx"); !ok {
+		t.Error("no edge from App.jsx to Button.jsx")
+	}
+	for id := range project.NodesMap {
+		if strings.Contains(id, "node_modules") {
+			t.Errorf("node_modules file %s was scanned", id)
+		}
+	}
+}
+
+func TestScanArchiveSizeLimit(t *testing.T) {
+	zipPath := writeZip(t, map[string][]byte{
+		"src/App.jsx": []byte("export default function App() { return null }\n"),
+		"src/bomb.js": bytes.Repeat([]byte{' '}, maxArchiveFileSize+1),
+	})
+
+	if _, err := ScanArchive(zipPath); err == nil || !strings.Contains(err.Error(), "extraction limit") {
+		t.Errorf("ScanArchive error = %v, want the extraction limit error", err)
+	}
+}
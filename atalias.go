@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// atImportRegex matches a specifier using the conventional "@/" alias
+var atImportRegex = regexp.MustCompile(`['"]@/([^'"]+)['"]`)
+
+// Limits on the sample inferAtAlias takes: it stops after reading this many
+// files or collecting this many "@/" specifiers
+const (
+	atProbeFiles   = 200
+	atProbeImports = 20
+)
+
+// inferAtAlias maps the "@" alias when no configuration covers "@/" imports.
+// It samples "@/..." specifiers from the project's files, checks each against
+// both src and the project root, and maps "@" to whichever resolves more of
+// them, preferring src on a tie. The choice is recorded in Inferred.
+func inferAtAlias(src sourceFS, rootDir string, config AliasConfig, opts ScanOptions) AliasConfig {
+	if config.isAlias("@/probe") {
+		return config
+	}
+
+	samples := []string{}
+	read := 0
+	_ = src.Walk(rootDir, opts.FollowSymlinks, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != rootDir && opts.skipsDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isReactFile(info.Name()) {
+			return nil
+		}
+
+		content, err := src.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, match := range atImportRegex.FindAllStringSubmatch(string(content), -1) {
+			samples = append(samples, strings.SplitN(match[1], "?", 2)[0])
+		}
+		if read++; read >= atProbeFiles || len(samples) >= atProbeImports {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if len(samples) == 0 {
+		return config
+	}
+
+	// Count the samples each candidate root resolves
+	roots := []string{"src", "."}
+	hits := make([]int, len(roots))
+	for i, root := range roots {
+		for _, sample := range samples {
+			if moduleExists(src, filepath.Join(rootDir, root, filepath.FromSlash(sample))) {
+				hits[i]++
+			}
+		}
+	}
+	best := 0
+	if hits[1] > hits[0] {
+		best = 1
+	}
+	if hits[best] == 0 {
+		return config
+	}
+
+	// The target is relative to the root, which the merge rebases
+	inferred := MergeAliasConfig(config, AliasConfig{Aliases: map[string]string{"@": roots[best]}})
+	inferred.Inferred = map[string]string{"@": roots[best]}
+	return inferred
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferAtAlias(t *testing.T) {
+	app := "import { Button } from '@/components/Button'\nexport default function App() { return <Button /> }\n"
+	button := "export const Button = () => <button />\n"
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		from     string
+		target   string
+		inferred map[string]string
+	}{
+		{
+			name: "src",
+			files: map[string]string{
+				"src/App.tsx":               app,
+				"src/components/Button.tsx": button,
+			},
+			from:     "src/App.tsx",
+			target:   "src/components/Button.tsx",
+			inferred: map[string]string{"@": "src"},
+		},
+		{
+			name: "root",
+			files: map[string]string{
+				"App.tsx":               app,
+				"components/Button.tsx": button,
+			},
+			from:     "App.tsx",
+			target:   "components/Button.tsx",
+			inferred: map[string]string{"@": "."},
+		},
+		{
+			name: "tie prefers src",
+			files: map[string]string{
+				"src/App.tsx":               app,
+				"src/components/Button.tsx": button,
+				"components/Button.tsx":     button,
+			},
+			from:     "src/App.tsx",
+			target:   "src/components/Button.tsx",
+			inferred: map[string]string{"@": "src"},
+		},
+		{
+			name: "configured",
+			files: map[string]string{
+				"tsconfig.json":             `{"compilerOptions": {"paths": {"@/*": ["lib/*"]}}}`,
+				"src/App.tsx":               app,
+				"src/components/Button.tsx": button,
+				"lib/components/Button.tsx": button,
+			},
+			from:   "src/App.tsx",
+			target: "lib/components/Button.tsx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := scanFiles(t, tt.files, ScanOptions{})
+			if _, ok := edgeTo(t, project, tt.from, tt.target); !ok {
+				t.Errorf("%s imports %v, want %s", tt.from, nodeAt(t, project, tt.from).Imports, tt.target)
+			}
+			if !reflect.DeepEqual(project.InferredAliases, tt.inferred) {
+				t.Errorf("InferredAliases = %v, want %v", project.InferredAliases, tt.inferred)
+			}
+		})
+	}
+}
@@ -1,27 +1,133 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
 
 // ComponentNode represents a component in the React project
 type ComponentNode struct {
-	ID           string          `json:"id"`
-	Name         string          `json:"name"`
-	Path         string          `json:"path"`
-	Type         string          `json:"type"` // component, state, util
-	MultipleComp bool            `json:"multipleComp"`
-	Imports      []string        `json:"imports"`
-	ImportedBy   []string        `json:"importedBy"`
-	Children     []ComponentNode `json:"children,omitempty"`
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Path              string            `json:"path"`
+	Type              string            `json:"type"` // component, state, util, config, test, route
+	MultipleComp      bool              `json:"multipleComp"`
+	Imports           []string          `json:"imports"`
+	ImportEdges       []ImportEdge      `json:"importEdges,omitempty"`
+	ImportedBy        []string          `json:"importedBy"`
+	ImportedByDetails []ImporterRef     `json:"importedByDetails,omitempty"`
+	Externals         []string          `json:"externalImports,omitempty"`
+	Packages          []ExternalPackage `json:"packages,omitempty"`        // per-package usage on the collapsed externals node
+	Exports           []string          `json:"exports,omitempty"`         // names the file exports ("default" for its default export)
+	PropsType         string            `json:"propsType,omitempty"`       // props interface or type of a TypeScript component
+	Props             []string          `json:"props,omitempty"`           // fields of PropsType
+	SliceName         string            `json:"sliceName,omitempty"`       // name of the Redux Toolkit slice the file creates
+	ReduxStore        bool              `json:"reduxStore,omitempty"`      // the file configures a Redux store
+	SelectedSlices    []string          `json:"selectedSlices,omitempty"`  // state keys read through selector hooks
+	RegistryMembers   map[string]string `json:"registryMembers,omitempty"` // member name -> component file
+	Routes            []RouteInfo       `json:"routes,omitempty"`          // route patterns served or declared by the file
+	DataRoutes        []RouteNode       `json:"dataRoutes,omitempty"`      // routes of the data routers the file creates
+	Complexity        Complexity        `json:"complexity"`
+	HasTest           bool              `json:"hasTest,omitempty"` // imported by at least one test file
+
+	// Git details, only populated when ScanOptions.IncludeGitInfo is set
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Owner        string     `json:"owner,omitempty"` // author of the most lines
+
+	// Rank is the node's PageRank over the import graph
+	Rank float64 `json:"rank,omitempty"`
+
+	// Hash is the SHA-256 of the file, only set with ScanOptions.IncludeFileHash
+	Hash string `json:"hash,omitempty"`
+
+	// ChangeStatus is set on nodes of an AnnotatedDiff: added, removed, changed or unchanged
+	ChangeStatus string `json:"changeStatus,omitempty"`
+
+	// Pinned marks nodes the project's .reactviz.json asks to highlight
+	Pinned bool `json:"pinned,omitempty"`
+
+	// HTMLEntry marks scripts loaded by index.html or public/index.html
+	HTMLEntry bool `json:"htmlEntry,omitempty"`
+
+	// Collapsed lists the nodes folded into a node of type "collapsed" by
+	// CollapseSubtree
+	Collapsed []string `json:"collapsed,omitempty"`
+
+	// Transitive closures, only populated when ScanOptions.ComputeClosures is set
+	TransitiveDeps       []string        `json:"transitiveDeps,omitempty"`
+	TransitiveDependents []string        `json:"transitiveDependents,omitempty"`
+	Children             []ComponentNode `json:"children,omitempty"`
+}
+
+// ImportEdge describes a single dependency from a file to another module
+type ImportEdge struct {
+	Target string `json:"target"`
+	Kind   string `json:"kind"`            // import, worker, require, dynamic, dynamicGlob, lazy, reexport, styleModule
+	Query  string `json:"query,omitempty"` // bundler query suffix, e.g. raw, url, worker, inline
+	Dev    bool   `json:"dev,omitempty"`
+	Line   int    `json:"line,omitempty"` // 1-based line of the import statement
+
+	// OutOfRoot is set when the target lies outside the scanned directory, e.g.
+	// through an alias to ../shared, and is not scanned as a node. Targets in
+	// source roots configured outside the directory are scanned instead.
+	OutOfRoot bool `json:"outOfRoot,omitempty"`
+
+	// Conditional is set for imports gated behind an environment check
+	Conditional bool   `json:"conditional,omitempty"`
+	Condition   string `json:"condition,omitempty"`
+
+	// Count is how many edges were merged into this one by CollapseSubtree
+	Count int `json:"count,omitempty"`
+
+	// Pattern is the glob a dynamicGlob edge may load from its target directory
+	Pattern string `json:"pattern,omitempty"`
+
+	// Route is the path of the route config object a lazy import sits in
+	Route string `json:"route,omitempty"`
+
+	// Bindings taken from the target by an import statement
+	Default   string   `json:"default,omitempty"`   // local name of the default import
+	Named     []string `json:"named,omitempty"`     // imported named symbols
+	Namespace string   `json:"namespace,omitempty"` // local name of a namespace import
+	Members   []string `json:"members,omitempty"`   // members accessed through the import, e.g. DS.Button
+
+	// LocalNames maps imported names bound under another name to their local
+	// binding, e.g. {"Button": "MyButton"} for { Button as MyButton } or
+	// {"default": "MyButton"} for { default as MyButton }
+	LocalNames map[string]string `json:"localNames,omitempty"`
+
+	// RenderCount is how often the imported components are rendered, through
+	// JSX tags or createElement/h calls
+	RenderCount int `json:"renderCount,omitempty"`
+
+	// ChangeStatus is set on edges of an AnnotatedDiff: added, removed or unchanged
+	ChangeStatus string `json:"changeStatus,omitempty"`
+
+	// Re-export details for export ... from statements
+	Reexports []ExportAlias `json:"reexports,omitempty"`
+	Star      bool          `json:"star,omitempty"` // export * from
+
+	// Set on edges created by barrel flattening
+	Via     string            `json:"via,omitempty"`     // the barrel the symbols were imported through
+	Renamed map[string]string `json:"renamed,omitempty"` // original name -> name imported from the barrel
+}
+
+// ImporterRef describes how an importing file references a node
+type ImporterRef struct {
+	Importer  string   `json:"importer"`
+	Default   bool     `json:"default,omitempty"`
+	Named     []string `json:"named,omitempty"`
+	Namespace bool     `json:"namespace,omitempty"`
 }
 
 // Project represents the entire React project structure
@@ -30,6 +136,74 @@ type Project struct {
 	NodesMap map[string]ComponentNode `json:"nodesMap"`
 	Files    []string                 `json:"files"`
 	Stats    ProjectStats             `json:"stats"`
+
+	ScanMetrics ScanMetrics `json:"scanMetrics"`
+
+	// Entry points used for reachability and the nodes none of them reach
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	Unreachable []string `json:"unreachable,omitempty"`
+
+	// Untested lists component and util nodes that no test file imports
+	Untested []string `json:"untested,omitempty"`
+
+	// ScanWarnings collects non-fatal problems encountered during the scan
+	ScanWarnings []string `json:"scanWarnings,omitempty"`
+
+	// ProviderStack lists the context providers wrapping the app, outermost first
+	ProviderStack []string `json:"providerStack,omitempty"`
+
+	// ReduxSlices relates Redux Toolkit slices to their store and consumers
+	ReduxSlices []ReduxSlice `json:"reduxSlices,omitempty"`
+
+	// BarrelChains are the longest re-export chains leading to each source file
+	BarrelChains []BarrelChain `json:"barrelChains,omitempty"`
+
+	// Chunks are the code-split chunks created by lazy imports
+	Chunks []Chunk `json:"chunks,omitempty"`
+
+	// Clusters are groups of mutually dependent modules (strongly connected components)
+	Clusters [][]string `json:"clusters,omitempty"`
+
+	// Cycles classifies each cluster as staying within a directory or crossing them
+	Cycles []Cycle `json:"cycles,omitempty"`
+
+	// InferredAliases lists aliases guessed from the project's imports because
+	// no configuration declared them, mapped to their directory
+	InferredAliases map[string]string `json:"inferredAliases,omitempty"`
+
+	// RouteTree holds the route hierarchies of React Router data routers
+	// created with createBrowserRouter and its siblings
+	RouteTree []RouteNode `json:"routeTree,omitempty"`
+
+	// ResolutionWarnings flags same-named files in different packages that are
+	// each imported, a likely copy or inconsistent import path
+	ResolutionWarnings []ResolutionWarning `json:"resolutionWarnings,omitempty"`
+
+	// MostComplex lists the components with the highest complexity scores
+	MostComplex []string `json:"mostComplex,omitempty"`
+
+	// TopRanked lists the nodes with the highest PageRank, most central first
+	TopRanked []string `json:"topRanked,omitempty"`
+
+	// UsageRanking lists every component by how many files import it and how
+	// often it is rendered, most used first
+	UsageRanking []ComponentUsage `json:"usageRanking,omitempty"`
+
+	// TreeRoot is the directory the tree starts at, in the same form as node IDs
+	TreeRoot string `json:"treeRoot,omitempty"`
+
+	// Legend maps each node type to its rendering style
+	Legend map[string]NodeStyle `json:"legend"`
+}
+
+// ScanMetrics records where time went during a scan. Durations are in nanoseconds.
+type ScanMetrics struct {
+	WalkDuration         time.Duration `json:"walkDuration"`  // whole directory walk, including parsing
+	ParseDuration        time.Duration `json:"parseDuration"` // time spent parsing files
+	RelationshipDuration time.Duration `json:"relationshipDuration"`
+	TotalDuration        time.Duration `json:"totalDuration"`
+	FileCount            int           `json:"fileCount"`
+	BytesRead            int64         `json:"bytesRead"`
 }
 
 // ProjectStats contains statistics about the project
@@ -39,15 +213,51 @@ type ProjectStats struct {
 	ComponentFiles  int `json:"componentFiles"`
 	StateFiles      int `json:"stateFiles"`
 	UtilFiles       int `json:"utilFiles"`
+	TestFiles       int `json:"testFiles"`
+	RouteFiles      int `json:"routeFiles"`
+	ConfigFiles     int `json:"configFiles"`
+
+	// Import edge counts; production edges exclude those touching tests or stories
+	ImportEdges           int `json:"importEdges"`
+	DevImportEdges        int `json:"devImportEdges"`
+	ProductionImportEdges int `json:"productionImportEdges"`
+	OutOfRootEdges        int `json:"outOfRootEdges"`
+
+	ExternalPackages []ExternalPackage `json:"externalPackages"`
+
+	// RenderDepth is the longest chain of render edges from an entry point,
+	// i.e. how deeply components nest inside one another
+	RenderDepth int `json:"renderDepth"`
+
+	// AggregateHash combines every node's Hash in a stable order; it is only
+	// set with ScanOptions.IncludeFileHash
+	AggregateHash string `json:"aggregateHash,omitempty"`
+}
+
+// ExternalPackage records how often a third-party package is imported
+type ExternalPackage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
 }
 
 // ScanProject scans a React project directory and returns a Project structure
 func ScanProject(rootDir string) (Project, error) {
-	// Read project configuration for import aliases
-	aliasConfig, err := ReadProjectConfig(rootDir)
-	if err != nil {
-		log.Printf("Warning: Could not read project config: %v, using defaults", err)
-	}
+	return ScanProjectWithOptions(rootDir, DefaultScanOptions())
+}
+
+// ScanProjectFS scans a project held in fsys, such as an fstest.MapFS or an
+// overlay of unsaved editor buffers. rootDir names the project in the output.
+func ScanProjectFS(fsys fs.FS, rootDir string, opts ScanOptions) (Project, error) {
+	opts.FS = fsys
+	return ScanProjectWithOptions(rootDir, opts)
+}
+
+// ScanProjectWithOptions scans a React project directory using the given options
+func ScanProjectWithOptions(rootDir string, opts ScanOptions) (Project, error) {
+	scanStart := time.Now()
+
+	logger := opts.logger()
+	src := newSourceFS(rootDir, opts.FS)
 
 	project := Project{
 		Root: ComponentNode{
@@ -60,62 +270,296 @@ func ScanProject(rootDir string) (Project, error) {
 		Files:    []string{},
 	}
 
+	// warn records a non-fatal problem in the project and logs it
+	warn := func(format string, args ...any) {
+		message := fmt.Sprintf(format, args...)
+		project.ScanWarnings = append(project.ScanWarnings, message)
+		logger.Warn(message)
+	}
+
+	aliasConfig := scanAliasConfig(src, rootDir, opts, warn)
+	project.InferredAliases = aliasConfig.Inferred
+
 	// Walk through the project directory
-	err = filepath.Walk(rootDir, func(path string, info fs.FileInfo, err error) error {
+	walkStart := time.Now()
+	sourceRoots := outsideSourceRoots(src, rootDir, aliasConfig)
+	scanned := make(map[string]bool) // a file reached twice, e.g. through a symlink, is parsed once
+	visit := func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip node_modules, build directories, and hidden files
-		if info.IsDir() && (info.Name() == "node_modules" || info.Name() == "build" ||
-			info.Name() == "dist" || strings.HasPrefix(info.Name(), ".")) {
+		// Skip node_modules, build directories, hidden files, and user-configured directories
+		if info.IsDir() && path != rootDir && !containsString(sourceRoots, path) && opts.skipsDir(info.Name()) {
 			return filepath.SkipDir
 		}
 
 		// Process only JS/TS/JSX/TSX files
 		if !info.IsDir() && isReactFile(info.Name()) {
 			relPath, _ := filepath.Rel(rootDir, path)
-			project.Files = append(project.Files, relPath)
+			if scanned[relPath] {
+				return nil
+			}
+			scanned[relPath] = true
 
 			// Parse the file to extract components and dependencies
-			node, err := parseFile(path, relPath, rootDir, aliasConfig)
+			parseStart := time.Now()
+			node, err := withTimeout(opts.ParseTimeout, func(ctx context.Context) (ComponentNode, error) {
+				return parseFile(ctx, src, path, relPath, rootDir, aliasConfig, opts)
+			})
+			if errors.Is(err, errParseTimeout) {
+				warn("Skipping %s: parsing took longer than %v", relPath, opts.ParseTimeout)
+				return nil
+			}
 			if err != nil {
 				return err
 			}
+			project.Files = append(project.Files, relPath)
+			project.ScanMetrics.ParseDuration += time.Since(parseStart)
+			project.ScanMetrics.FileCount++
+			project.ScanMetrics.BytesRead += info.Size()
 
 			if node.Name != "" {
 				project.NodesMap[node.ID] = node
-
-				// Update stats
-				project.Stats.TotalComponents++
-				if node.Type == "component" {
-					project.Stats.ComponentFiles++
-					if node.MultipleComp {
-						project.Stats.MultiCompFiles++
-					}
-				} else if node.Type == "state" {
-					project.Stats.StateFiles++
-				} else if node.Type == "util" {
-					project.Stats.UtilFiles++
-				}
 			}
 		}
 
 		return nil
-	})
+	}
+	err := src.Walk(rootDir, opts.FollowSymlinks, visit)
+
+	// Source roots configured outside the scanned directory, such as a sibling
+	// in tsconfig rootDirs, are walked too so imports crossing into them link
+	for _, sourceRoot := range sourceRoots {
+		if err == nil {
+			err = src.Walk(sourceRoot, opts.FollowSymlinks, visit)
+		}
+	}
 	if err != nil {
 		return project, err
 	}
+	for id, node := range project.NodesMap {
+		for i, edge := range node.ImportEdges {
+			if _, scanned := project.NodesMap[edge.Target]; scanned && edge.OutOfRoot {
+				node.ImportEdges[i].OutOfRoot = false
+			}
+		}
+		project.NodesMap[id] = node
+	}
+
+	// The walk skips node_modules, but aliases may point into it
+	if err := scanNodeModulesTargets(&project, src, rootDir, aliasConfig, opts, warn); err != nil {
+		return project, err
+	}
+	project.ScanMetrics.WalkDuration = time.Since(walkStart)
+
+	// Mark file-system routes and tally per-type statistics
+	detectRoutes(&project, src, rootDir)
+	for _, node := range project.NodesMap {
+		addNodeStats(&project.Stats, node)
+	}
+	project.Stats.AggregateHash = aggregateHash(project)
 
 	// Build relationships between components
+	relationshipStart := time.Now()
+	linkRegistryMembers(&project)
+	project.BarrelChains = findBarrelChains(project)
+	if opts.FlattenBarrels {
+		flattenBarrels(&project)
+	}
 	buildRelationships(&project)
 
-	// Build the tree structure
+	// Separate dev-only edges from production coupling
+	markDevEdges(&project)
+	if project.Stats.OutOfRootEdges > 0 {
+		warn("%d imports resolve outside %s and are not scanned", project.Stats.OutOfRootEdges, rootDir)
+	}
+
+	if opts.ComputeClosures {
+		computeClosures(&project)
+	}
+	project.ScanMetrics.RelationshipDuration = time.Since(relationshipStart)
+
+	// Summarize the external packages the project depends on
+	project.Stats.ExternalPackages = countExternalPackages(project.NodesMap)
+
+	// Flag the scripts the HTML page loads, whichever entries are used
+	markHTMLEntries(&project, src, rootDir)
+
+	// Report nodes that are not reachable from any entry point
+	for _, entry := range opts.Entries {
+		project.EntryPoints = append(project.EntryPoints, filepath.FromSlash(entry))
+	}
+	if len(project.EntryPoints) == 0 {
+		project.EntryPoints = detectEntryPoints(project, src, rootDir)
+	}
+	if len(project.EntryPoints) > 0 {
+		project.Unreachable = UnreachableFrom(project, project.EntryPoints)
+	}
+
+	// Measure how deeply components nest from the entry points
+	project.Stats.RenderDepth = renderDepth(project, project.EntryPoints)
+
+	// Find source files without a test importing them
+	project.Untested = markTestedNodes(&project)
+
+	// Record the providers wrapping the app root
+	project.ProviderStack = detectProviderStack(project, src, rootDir, project.EntryPoints)
+
+	// Relate Redux slices to their store and consumers
+	project.ReduxSlices = linkReduxSlices(project)
+
+	// Build the route hierarchy of data routers
+	project.RouteTree = buildRouteTree(project)
+
+	// Annotate nodes with ownership and recency from Git
+	if opts.IncludeGitInfo {
+		if err := EnrichWithGit(rootDir, &project); err != nil {
+			warn("Skipping Git info: %v", err)
+		}
+	}
+
+	// Group lazily loaded code into chunks
+	project.Chunks = computeChunks(project, project.EntryPoints)
+
+	// Find clusters of entangled modules
+	project.Clusters = StronglyConnectedComponents(project)
+	project.Cycles = classifyCycles(project.Clusters)
+
+	// Spot shared files imported from more than one package
+	project.ResolutionWarnings = findResolutionWarnings(project, src, rootDir)
+
+	// Rank components by complexity
+	project.MostComplex = mostComplexComponents(project, mostComplexLimit)
+
+	// Rank nodes by how central they are to the import graph
+	computeRanks(&project)
+	project.TopRanked = topRanked(project, topRankedLimit)
+
+	// Rank components by adoption
+	project.UsageRanking = usageRanking(project)
+
+	// Publish consistent styling for every node type
+	project.Legend = buildLegend(project, opts.TypeStyles)
+
+	// Build the tree structure from the chosen root; NodesMap stays complete
+	project.TreeRoot = resolveTreeRoot(opts.TreeRoot, project, aliasConfig)
+	if project.TreeRoot != "" {
+		project.Root.Name = filepath.Base(project.TreeRoot)
+		project.Root.Path = filepath.Join(rootDir, project.TreeRoot)
+	}
+	if opts.ProjectName != "" {
+		project.Root.Name = opts.ProjectName
+	} else if project.TreeRoot == "" {
+		if name := manifestName(src, rootDir); name != "" {
+			project.Root.Name = name
+		}
+	}
 	buildTree(&project)
 
+	// Apply the curation in .reactviz.json
+	if sidecar, err := readSidecar(src, rootDir); err != nil {
+		warn("Ignoring %v", err)
+	} else if sidecar != nil {
+		var unknown []string
+		project, unknown = applySidecar(project, *sidecar)
+		for _, id := range unknown {
+			warn("%s lists %s, which is not in the project", sidecarFile, id)
+		}
+	}
+
+	// Hide nodes below the fan-in and fan-out thresholds
+	if opts.MinFanIn > 0 || opts.MinFanOut > 0 {
+		project = FilterByFanInOut(project, opts.MinFanIn, opts.MinFanOut)
+	}
+
+	// Keep only the edges not implied by others
+	if opts.TransitiveReduction {
+		project = TransitiveReduction(project)
+	}
+
+	// Re-express paths relative to the requested base
+	if err := rebasePaths(&project, rootDir, opts.PathBase); err != nil {
+		return project, err
+	}
+
+	// Replace proprietary names last so every analysis above sees real paths
+	if opts.Anonymize {
+		anonymizeProject(&project)
+	}
+
+	// Fold third-party packages into one node once no pass is left to count it
+	if opts.CollapseExternals {
+		collapseExternals(&project)
+	}
+
+	project.ScanMetrics.TotalDuration = time.Since(scanStart)
+	if opts.LogMetrics {
+		m := project.ScanMetrics
+		logger.Info("Scan complete",
+			"files", m.FileCount,
+			"bytes", m.BytesRead,
+			"total", m.TotalDuration,
+			"walk", m.WalkDuration,
+			"parse", m.ParseDuration,
+			"relationships", m.RelationshipDuration)
+	}
+
 	return project, nil
 }
 
+// addNodeStats counts a node in the per-type statistics
+func addNodeStats(stats *ProjectStats, node ComponentNode) {
+	stats.TotalComponents++
+	if node.Type == "component" {
+		stats.ComponentFiles++
+		if node.MultipleComp {
+			stats.MultiCompFiles++
+		}
+	} else if node.Type == "state" {
+		stats.StateFiles++
+	} else if node.Type == "util" {
+		stats.UtilFiles++
+	} else if node.Type == "test" {
+		stats.TestFiles++
+	} else if node.Type == "route" {
+		stats.RouteFiles++
+	} else if node.Type == "config" {
+		stats.ConfigFiles++
+	}
+}
+
+// scanAliasConfig reads the project's alias configuration, applies the
+// override of opts and infers the "@" alias, reporting problems through warn
+func scanAliasConfig(src sourceFS, rootDir string, opts ScanOptions, warn func(format string, args ...any)) AliasConfig {
+	aliasConfig, err := withTimeout(opts.ParseTimeout, func(ctx context.Context) (AliasConfig, error) {
+		return readProjectConfig(ctx, src, rootDir)
+	})
+	if errors.Is(err, errParseTimeout) {
+		aliasConfig = AliasConfig{Aliases: make(map[string]string), ExactAliases: make(map[string]string)}
+		warn("Reading project config took longer than %v, using defaults", opts.ParseTimeout)
+	} else if err != nil {
+		warn("Could not read project config: %v, using defaults", err)
+	}
+	if opts.AliasOverride != nil {
+		aliasConfig = MergeAliasConfig(aliasConfig, *opts.AliasOverride)
+	}
+
+	// Without configuration for "@/" imports, infer whether "@" means src or the root
+	aliasConfig = inferAtAlias(src, rootDir, aliasConfig, opts)
+
+	// Pathological alias configs would silently produce a wrong graph
+	for _, warning := range CheckAliasCycles(aliasConfig) {
+		warn("%s", warning)
+	}
+	return aliasConfig
+}
+
+// isSkippedDir reports whether a directory is excluded from scanning by default
+func isSkippedDir(name string) bool {
+	return name == "node_modules" || name == "build" || name == "dist" || strings.HasPrefix(name, ".")
+}
+
 // isReactFile checks if a file is a React-related file
 func isReactFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -123,13 +567,18 @@ func isReactFile(filename string) bool {
 }
 
 // parseFile extracts component information from a file
-func parseFile(path, relPath string, rootDir string, aliasConfig AliasConfig) (ComponentNode, error) {
-	content, err := os.ReadFile(path)
+func parseFile(ctx context.Context, src sourceFS, path, relPath string, rootDir string, aliasConfig AliasConfig, opts ScanOptions) (ComponentNode, error) {
+	content, err := src.ReadFile(path)
 	if err != nil {
 		return ComponentNode{}, err
 	}
+	return parseContent(ctx, src, string(content), path, relPath, rootDir, aliasConfig, opts)
+}
 
-	fileContent := string(content)
+// parseContent builds the node of the file at path from its content, which
+// may differ from what is on disk, such as an earlier revision. It gives up
+// with the context's error between passes once ctx is done.
+func parseContent(ctx context.Context, src sourceFS, fileContent, path, relPath string, rootDir string, aliasConfig AliasConfig, opts ScanOptions) (ComponentNode, error) {
 	fileName := filepath.Base(path)
 	fileNameWithoutExt := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
@@ -140,11 +589,12 @@ func parseFile(path, relPath string, rootDir string, aliasConfig AliasConfig) (C
 		parentDir := filepath.Dir(relPath)
 		// If relPath is directly "index.js", use the project name
 		if parentDir == "." {
-			parentDir = filepath.Base(rootDir)
-		} else {
-			// Otherwise use the directory name
-			componentName = filepath.Base(parentDir) + "/index"
+			parentDir = rootDir
+			if absRoot, err := filepath.Abs(rootDir); err == nil {
+				parentDir = absRoot
+			}
 		}
+		componentName = filepath.Base(parentDir) + "/index"
 	}
 
 	node := ComponentNode{
@@ -154,21 +604,110 @@ func parseFile(path, relPath string, rootDir string, aliasConfig AliasConfig) (C
 		Imports:    []string{},
 		ImportedBy: []string{},
 	}
+	if opts.IncludeFileHash {
+		node.Hash = fileHash([]byte(fileContent))
+	}
 
-	// Determine file type
-	if isComponentFile(fileContent, fileName) {
+	// Determine file type, giving a custom classifier the first say
+	if nodeType, ok := classifyCustom(opts, relPath, fileContent); ok {
+		node.Type = nodeType
+		if nodeType == "component" {
+			node.MultipleComp = hasMultipleComponents(fileContent)
+		}
+	} else if isTestFile(relPath) {
+		node.Type = "test"
+	} else if isComponentFile(fileContent, fileName) {
 		node.Type = "component"
 		node.MultipleComp = hasMultipleComponents(fileContent)
-	} else if isStateFile(fileContent, relPath) {
+	} else if isStateFile(fileContent, relPath, opts.StateSignatures) {
 		node.Type = "state"
+	} else if isConfigFile(fileContent, fileNameWithoutExt) {
+		node.Type = "config"
 	} else {
 		node.Type = "util"
 	}
 
+	if err := ctx.Err(); err != nil {
+		return ComponentNode{}, err
+	}
+
 	// Extract imports
-	node.Imports = extractImports(fileContent, filepath.Dir(relPath), rootDir, aliasConfig)
+	node.ImportEdges, node.Externals = extractImports(src, fileContent, filepath.Dir(relPath), rootDir, aliasConfig, opts)
+	node.Imports = edgeTargets(node.ImportEdges)
+	recordPassThroughExports(fileContent, node.ImportEdges)
+	node.Exports = findExports(fileContent)
+	if opts.UseAST {
+		if exports, err := tokenExports(fileContent); err == nil {
+			node.Exports = exports
+		}
+	}
 
-	return node, nil
+	if err := ctx.Err(); err != nil {
+		return ComponentNode{}, err
+	}
+
+	// Catalog the props of TypeScript components
+	if node.Type == "component" {
+		node.PropsType, node.Props = findProps(fileContent)
+		if opts.UseAST {
+			if propsType, props, err := tokenProps(fileContent); err == nil {
+				node.PropsType, node.Props = propsType, props
+			}
+		}
+	}
+
+	// Note the Redux Toolkit role of the file
+	node.SliceName = findSliceName(fileContent)
+	node.ReduxStore = configureStoreRegex.MatchString(fileContent)
+	node.SelectedSlices = findSelectedSlices(fileContent)
+
+	if err := ctx.Err(); err != nil {
+		return ComponentNode{}, err
+	}
+
+	// Score how complex the file is
+	node.Complexity = measureComplexity(fileContent, len(node.ImportEdges)+len(node.Externals))
+
+	// Record React Router route patterns declared in this file
+	node.Routes = findRouteDefinitions(fileContent)
+	node.DataRoutes = findDataRoutes(fileContent, node)
+
+	// Detect component registries and member access through them
+	node.RegistryMembers = findRegistryMembers(fileContent, node.ImportEdges)
+	recordMemberAccess(fileContent, node.ImportEdges)
+
+	// Count how often imported components are rendered
+	recordRenders(fileContent, node.ImportEdges)
+
+	return node, ctx.Err()
+}
+
+// classifyCustom runs the user-supplied classifier, if any
+func classifyCustom(opts ScanOptions, relPath, content string) (string, bool) {
+	if opts.Classify == nil {
+		return "", false
+	}
+	nodeType, ok := opts.Classify(relPath, content)
+	if !ok || nodeType == "" {
+		return "", false
+	}
+	return nodeType, true
+}
+
+// isTestFile determines if a file is a test, story, or test utility
+func isTestFile(path string) bool {
+	path = filepath.ToSlash(path)
+	fileName := strings.ToLower(filepath.Base(path))
+
+	return strings.Contains(fileName, ".test.") ||
+		strings.Contains(fileName, ".spec.") ||
+		strings.Contains(fileName, ".stories.") ||
+		strings.Contains(fileName, ".story.") ||
+		strings.HasPrefix(fileName, "test-utils.") ||
+		strings.HasPrefix(fileName, "testutils.") ||
+		strings.HasPrefix(fileName, "setuptests.") ||
+		strings.Contains(path, "__tests__/") ||
+		strings.Contains(path, "__mocks__/")
 }
 
 // isComponentFile determines if a file contains React components
@@ -184,7 +723,7 @@ func isComponentFile(content, fileName string) bool {
 	// If filename starts with uppercase, it's likely a component
 	startsWithUppercase := len(fileName) > 0 && fileName[0] >= 'A' && fileName[0] <= 'Z'
 
-	return (hasReactImport && (hasJSXReturn || hasComponentDef)) || startsWithUppercase
+	return (hasReactImport && (hasJSXReturn || hasComponentDef)) || usesCreateElement(content) || startsWithUppercase
 }
 
 // hasMultipleComponents checks if a file contains multiple component definitions
@@ -194,8 +733,54 @@ func hasMultipleComponents(content string) bool {
 	return len(componentDefs) > 1
 }
 
-// isStateFile determines if a file is related to state management
-func isStateFile(content, path string) bool {
+// configFileNames are base names conventionally used for config and constants modules
+var configFileNames = map[string]bool{
+	"config": true, "configs": true, "constants": true, "consts": true, "settings": true, "env": true,
+}
+
+// logicRegex matches code that does more than declare data: functions, arrows,
+// classes, mutable bindings and JSX
+var logicRegex = regexp.MustCompile(`\bfunction\b|=>|\bclass\s|\b(?:let|var)\s|</|/>`)
+
+// constantExportRegex matches an exported constant or default-exported literal
+var constantExportRegex = regexp.MustCompile(`export\s+(?:const\s|default\s+[{\['"\d])`)
+
+// isConfigFile determines if a file is an inert config or constants module,
+// either by name (constants.ts, app.config.js) or because it only exports
+// constant data and contains no logic or value imports
+func isConfigFile(content, baseName string) bool {
+	name := strings.ToLower(baseName)
+	if configFileNames[name] || strings.HasSuffix(name, ".config") || strings.HasSuffix(name, ".constants") {
+		return true
+	}
+	code := stripNonCode(content)
+	if !constantExportRegex.MatchString(code) || logicRegex.MatchString(code) || requireRegex.MatchString(code) {
+		return false
+	}
+	// Modules built from imported values, such as component registries, are not inert
+	for _, match := range importRegex.FindAllStringSubmatch(code, -1) {
+		if !strings.HasPrefix(strings.TrimSpace(match[1]), "type ") {
+			return false
+		}
+	}
+	return true
+}
+
+// reducerSwitchRegex matches a switch over an action's type, as in a reducer function
+var reducerSwitchRegex = regexp.MustCompile(`switch\s*\(\s*(?:action|\w+)\.type\s*\)`)
+
+// reducerSignatureRegex matches a (state, action) parameter list
+var reducerSignatureRegex = regexp.MustCompile(`\(\s*state\b[^)]*,\s*action\b`)
+
+// isReducerDefinition reports whether content defines a reducer function:
+// one taking (state, action) and switching over the action type
+func isReducerDefinition(content string) bool {
+	return reducerSwitchRegex.MatchString(content) && reducerSignatureRegex.MatchString(content)
+}
+
+// isStateFile determines if a file is related to state management.
+// Extra signatures are matched against the content alongside the built-in ones.
+func isStateFile(content, path string, signatures []string) bool {
 	// Check for Redux patterns
 	isRedux := strings.Contains(content, "createStore") ||
 		strings.Contains(content, "combineReducers") ||
@@ -213,79 +798,539 @@ func isStateFile(content, path string) bool {
 		strings.Contains(content, "zustand") ||
 		strings.Contains(content, "recoil") ||
 		strings.Contains(content, "jotai") ||
-		strings.Contains(content, "mobx")
+		strings.Contains(content, "mobx") ||
+		strings.Contains(content, "useReducer(") ||
+		isReducerDefinition(content)
+
+	// Check for user-declared state library signatures
+	for _, signature := range signatures {
+		if signature != "" && strings.Contains(content, signature) {
+			return true
+		}
+	}
 
 	return isRedux || isOtherState
 }
 
-// extractImports extracts import statements from file content
-func extractImports(content, dir string, rootDir string, aliasConfig AliasConfig) []string {
-	imports := []string{}
+// importRegex matches static ES import statements, capturing the import clause and specifier
+var importRegex = regexp.MustCompile(`import\s+([\w$*{}\s,]+?)\s+from\s+['"]([^'"]+)['"]`)
 
-	// Find all import statements
-	importRegex := regexp.MustCompile(`import\s+(?:{[^}]*}|\w+)\s+from\s+['"]([^'"]+)['"]`)
-	matches := importRegex.FindAllStringSubmatch(content, -1)
-
-	for _, match := range matches {
-		if len(match) > 1 {
-			importPath := match[1]
-
-			// Skip obvious node_modules imports (packages with @ or no path separators)
-			if strings.HasPrefix(importPath, "@") || !strings.Contains(importPath, "/") {
-				// But make an exception for path aliases that might be single words
-				isAlias := false
-				for alias := range aliasConfig.Aliases {
-					if importPath == alias || strings.HasPrefix(importPath, alias+"/") {
-						isAlias = true
-						break
+// sideEffectImportRegex matches imports run only for their side effects, such as import './global.css'
+var sideEffectImportRegex = regexp.MustCompile(`\bimport\s*['"]([^'"]+)['"]`)
+
+// namespaceImportRegex matches a namespace import clause such as "* as utils"
+var namespaceImportRegex = regexp.MustCompile(`\*\s*as\s+([\w$]+)`)
+
+// workerURLRegex matches module references of the form new URL('./worker.ts', import.meta.url)
+var workerURLRegex = regexp.MustCompile(`new\s+URL\(\s*['"]([^'"]+)['"]\s*,\s*import\.meta\.url\s*\)`)
+
+// requireRegex matches CommonJS require calls with a static specifier
+var requireRegex = regexp.MustCompile(`\brequire\(\s*['"]([^'"]+)['"]\s*\)`)
+
+// importEqualsRegex matches TypeScript import-equals declarations, capturing the
+// binding, the specifier, and the position of the require call
+var importEqualsRegex = regexp.MustCompile(`\bimport\s+([\w$]+)\s*=\s*(require\(\s*['"]([^'"]+)['"]\s*\))`)
+
+// dynamicImportRegex matches dynamic import() calls with a static specifier
+var dynamicImportRegex = regexp.MustCompile(`\bimport\(\s*['"]([^'"]+)['"]\s*\)`)
+
+// cssModuleRegex matches CSS Modules stylesheet specifiers
+var cssModuleRegex = regexp.MustCompile(`\.module\.(css|scss|sass|less)$`)
+
+// templateImportRegex matches dynamic import() calls with a template literal
+// containing substitutions, e.g. import(`./locales/${lang}.json`)
+var templateImportRegex = regexp.MustCompile("\\bimport\\(\\s*`([^`]*\\$\\{[^`]*)`\\s*\\)")
+
+// templateSubstitutionRegex matches a ${...} substitution in a template literal
+var templateSubstitutionRegex = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// extractImports extracts import statements from file content. Besides the
+// local edges it returns the names of the external packages that were skipped.
+func extractImports(src sourceFS, content, dir string, rootDir string, aliasConfig AliasConfig, opts ScanOptions) ([]ImportEdge, []string) {
+	edges := []ImportEdge{}
+	externals := []string{}
+
+	// addEdge resolves a specifier and records the edge, or the external package it names
+	addEdge := func(specifier string, edge ImportEdge) {
+		specifier, edge.Query = splitImportQuery(specifier)
+		if strings.Contains(edge.Query, "worker") {
+			edge.Kind = "worker"
+		} else if edge.Kind == "import" && cssModuleRegex.MatchString(specifier) {
+			edge.Kind = "styleModule"
+		}
+
+		resolvedPath, ok := resolveImport(src, specifier, dir, rootDir, aliasConfig, opts)
+		if !ok {
+			externals = append(externals, packageName(specifier))
+			return
+		}
+		edge.Target = resolvedPath
+		edge.OutOfRoot = isOutsideRoot(resolvedPath)
+		edges = append(edges, edge)
+	}
+
+	// addGlob records a coarse edge from a templated dynamic import to the
+	// directory named by the template's static prefix
+	addGlob := func(template string, line int) {
+		prefix := template[:strings.Index(template, "${")]
+		slash := strings.LastIndex(prefix, "/")
+		if slash < 0 {
+			return // nothing static to resolve
+		}
+		dirSpecifier := prefix[:slash]
+		if dirSpecifier == "" {
+			dirSpecifier = "/"
+		}
+		bare := !strings.HasPrefix(dirSpecifier, ".") && !strings.HasPrefix(dirSpecifier, "/") &&
+			!aliasConfig.isAlias(dirSpecifier) && !aliasConfig.inBaseDir(src, dirSpecifier, rootDir)
+		target, ok := resolveImportDir(src, dirSpecifier, dir, rootDir, aliasConfig, opts)
+		if bare || !ok {
+			externals = append(externals, packageName(dirSpecifier))
+			return
+		}
+		edges = append(edges, ImportEdge{
+			Target:    target,
+			Kind:      "dynamicGlob",
+			Pattern:   templateSubstitutionRegex.ReplaceAllString(template, "*"),
+			Line:      line,
+			OutOfRoot: isOutsideRoot(target),
+		})
+	}
+
+	conditions := findEnvConditions(content)
+	lines := lineOffsets(content)
+
+	// The token-based parser replaces the regexes below when it can lex the file
+	if opts.UseAST {
+		if found, err := tokenImports(content); err == nil {
+			code := stripNonCode(content)
+			for _, ref := range found {
+				if ref.template != "" {
+					addGlob(ref.template, lineAt(lines, ref.pos))
+					continue
+				}
+				edge := ref.edge
+				edge.Line = lineAt(lines, ref.pos)
+				if edge.Kind == "dynamic" || edge.Kind == "require" {
+					if edge.Kind == "dynamic" {
+						tagLazyImport(code, content, ref.pos, &edge)
+					}
+					if condition, gated := conditionAt(conditions, content, ref.pos); gated {
+						edge.Conditional = true
+						edge.Condition = condition
 					}
 				}
+				addEdge(ref.specifier, edge)
+			}
+			return edges, externals
+		}
+	}
 
-				if !isAlias && !strings.HasPrefix(importPath, ".") && !strings.HasPrefix(importPath, "/") {
-					continue // Skip this import as it's likely an external module
-				}
+	// Run the regexes over code only, so commented-out imports and import-like
+	// text in strings don't create edges. Offsets still match content.
+	code := stripNonCode(content)
+
+	// Find all import statements
+	for _, loc := range importRegex.FindAllStringSubmatchIndex(code, -1) {
+		edge := ImportEdge{Kind: "import", Line: lineAt(lines, loc[0])}
+		parseImportClause(code[loc[2]:loc[3]], &edge)
+		addEdge(code[loc[4]:loc[5]], edge)
+	}
+	for _, loc := range sideEffectImportRegex.FindAllStringSubmatchIndex(code, -1) {
+		addEdge(code[loc[2]:loc[3]], ImportEdge{Kind: "import", Line: lineAt(lines, loc[0])})
+	}
+
+	// Find re-exports: export { A as B } from './a' and export * from './b'
+	for _, loc := range reexportRegex.FindAllStringSubmatchIndex(code, -1) {
+		edge := ImportEdge{Kind: "reexport", Reexports: parseExportList(code[loc[2]:loc[3]]), Line: lineAt(lines, loc[0])}
+		addEdge(code[loc[4]:loc[5]], edge)
+	}
+	for _, loc := range starReexportRegex.FindAllStringSubmatchIndex(code, -1) {
+		edge := ImportEdge{Kind: "reexport", Star: loc[2] < 0, Line: lineAt(lines, loc[0])}
+		if loc[2] >= 0 {
+			edge.Reexports = []ExportAlias{{Local: "*", Exported: code[loc[2]:loc[3]]}}
+		}
+		addEdge(code[loc[4]:loc[5]], edge)
+	}
+
+	// Find modules loaded as web workers via import.meta.url
+	for _, loc := range workerURLRegex.FindAllStringSubmatchIndex(code, -1) {
+		addEdge(code[loc[2]:loc[3]], ImportEdge{Kind: "worker", Line: lineAt(lines, loc[0])})
+	}
+
+	// Find legacy TypeScript import-equals declarations: import Foo = require('./foo')
+	importEquals := make(map[int]bool)
+	for _, loc := range importEqualsRegex.FindAllStringSubmatchIndex(code, -1) {
+		importEquals[loc[4]] = true
+		addEdge(code[loc[6]:loc[7]], ImportEdge{Kind: "import", Default: code[loc[2]:loc[3]], Line: lineAt(lines, loc[0])})
+	}
+
+	// Find require() and import() calls, which may be gated behind environment checks
+	callPatterns := []struct {
+		kind  string
+		regex *regexp.Regexp
+	}{
+		{"require", requireRegex},
+		{"dynamic", dynamicImportRegex},
+	}
+
+	for _, pattern := range callPatterns {
+		for _, loc := range pattern.regex.FindAllStringSubmatchIndex(code, -1) {
+			// Already recorded as part of an import-equals declaration
+			if importEquals[loc[0]] {
+				continue
+			}
+
+			edge := ImportEdge{Kind: pattern.kind, Line: lineAt(lines, loc[0])}
+			if pattern.kind == "dynamic" {
+				tagLazyImport(code, content, loc[0], &edge)
 			}
+			if condition, gated := conditionAt(conditions, content, loc[0]); gated {
+				edge.Conditional = true
+				edge.Condition = condition
+			}
+			addEdge(code[loc[2]:loc[3]], edge)
+		}
+	}
+
+	// Find templated dynamic imports and link them to the directory of their static prefix
+	for _, loc := range templateImportRegex.FindAllStringSubmatchIndex(code, -1) {
+		addGlob(code[loc[2]:loc[3]], lineAt(lines, loc[0]))
+	}
+
+	return edges, externals
+}
+
+// tagLazyImport marks an import() call at offset as lazy when a lazy loader
+// wraps it or it sits in a route config object, recording the route's path
+func tagLazyImport(code, content string, offset int, edge *ImportEdge) {
+	edge.Route = routeAt(code, content, offset)
+	if edge.Route != "" || isLazyImport(content, offset) {
+		edge.Kind = "lazy"
+	}
+}
 
-			// Resolve the import path using our alias configuration
-			resolvedPath := ResolveImportPath(importPath, aliasConfig, rootDir, dir)
+// outsideSourceRoots returns the existing rootDirs and alias target
+// directories of the alias config that lie outside rootDir, as paths joined
+// with rootDir. Base directories never count, as a baseUrl only says where
+// bare specifiers start, and neither does a directory containing rootDir,
+// whose walk would cover the whole enclosing project. Roots nested in another
+// one are left out, as its walk covers them.
+func outsideSourceRoots(src sourceFS, rootDir string, aliasConfig AliasConfig) []string {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil
+	}
+
+	dirs := []string{}
+	for _, dir := range aliasConfig.RootDirs {
+		dirs = append(dirs, filepath.Join(rootDir, dir))
+	}
+	for _, target := range aliasConfig.Aliases {
+		dirs = append(dirs, aliasTargetPath(target, aliasConfig, rootDir))
+	}
 
-			// Make path relative to project root
-			relPath, err := filepath.Rel(rootDir, resolvedPath)
-			if err == nil {
-				resolvedPath = relPath
+	candidates := []string{}
+	for _, dir := range dirs {
+		// Normalize paths such as ../app/src that lead back into rootDir
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if dir, err = filepath.Rel(absRoot, absDir); err != nil || !isOutsideRoot(dir) || containsString(candidates, dir) {
+			continue
+		}
+		if enclosing, err := filepath.Rel(absDir, absRoot); err == nil && !isOutsideRoot(enclosing) {
+			continue
+		}
+		if info, err := src.Stat(filepath.Join(rootDir, dir)); err == nil && info.IsDir() {
+			candidates = append(candidates, dir)
+		}
+	}
+	sort.Strings(candidates)
+
+	roots := []string{}
+	for _, dir := range candidates {
+		nested := false
+		for _, other := range candidates {
+			if other != dir && pathWithin(dir, other) {
+				nested = true
 			}
+		}
+		if !nested {
+			roots = append(roots, filepath.Join(rootDir, dir))
+		}
+	}
+	return roots
+}
 
-			// Add extensions if missing
-			if !strings.Contains(filepath.Base(resolvedPath), ".") {
-				possibleExts := []string{".js", ".jsx", ".ts", ".tsx"}
-				foundExt := false
-
-				for _, ext := range possibleExts {
-					fullPath := filepath.Join(rootDir, resolvedPath+ext)
-					if _, err := os.Stat(fullPath); err == nil {
-						resolvedPath = resolvedPath + ext
-						foundExt = true
-						break
-					}
-				}
+// scanNodeModulesTargets parses the files under node_modules that scanned
+// files import by path, such as the target of a tsconfig alias like
+// "@utils": ["node_modules/@org/utils/src"], and then the files those import
+// in turn. Bare package imports stay external.
+func scanNodeModulesTargets(project *Project, src sourceFS, rootDir string, aliasConfig AliasConfig, opts ScanOptions, warn func(string, ...any)) error {
+	queue := []string{}
+	for _, id := range sortedNodeIDs(*project) {
+		queue = append(queue, project.NodesMap[id].Imports...)
+	}
+	scanned := make(map[string]bool)
+	for _, relPath := range project.Files {
+		scanned[relPath] = true
+	}
 
-				// Also try with /index.* extensions
-				if !foundExt {
-					for _, ext := range possibleExts {
-						fullPath := filepath.Join(rootDir, resolvedPath, "index"+ext)
-						if _, err := os.Stat(fullPath); err == nil {
-							resolvedPath = filepath.Join(resolvedPath, "index"+ext)
-							break
-						}
-					}
+	for len(queue) > 0 {
+		relPath := queue[0]
+		queue = queue[1:]
+		if scanned[relPath] || isOutsideRoot(relPath) ||
+			!containsString(strings.Split(filepath.ToSlash(relPath), "/"), "node_modules") ||
+			!isReactFile(filepath.Base(relPath)) {
+			continue
+		}
+
+		path := filepath.Join(rootDir, relPath)
+		info, err := src.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		node, err := withTimeout(opts.ParseTimeout, func(ctx context.Context) (ComponentNode, error) {
+			return parseFile(ctx, src, path, relPath, rootDir, aliasConfig, opts)
+		})
+		if errors.Is(err, errParseTimeout) {
+			warn("Skipping %s: parsing took longer than %v", relPath, opts.ParseTimeout)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		scanned[relPath] = true
+		project.Files = append(project.Files, relPath)
+		project.ScanMetrics.FileCount++
+		project.ScanMetrics.BytesRead += info.Size()
+		if node.Name == "" {
+			continue
+		}
+		project.NodesMap[node.ID] = node
+		queue = append(queue, node.Imports...)
+	}
+	return nil
+}
+
+// isOutsideRoot reports whether a root-relative path escapes the root
+func isOutsideRoot(relPath string) bool {
+	return relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
+
+// lineOffsets returns the byte offset at which each line of content starts
+func lineOffsets(content string) []int {
+	offsets := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// lineAt returns the 1-based line containing the byte offset pos
+func lineAt(offsets []int, pos int) int {
+	return sort.Search(len(offsets), func(i int) bool { return offsets[i] > pos })
+}
+
+// splitImportQuery separates a bundler query suffix such as "?raw" or "?worker"
+// from an import specifier
+func splitImportQuery(specifier string) (string, string) {
+	if idx := strings.Index(specifier, "?"); idx >= 0 {
+		return specifier[:idx], specifier[idx+1:]
+	}
+	return specifier, ""
+}
+
+// packageName reduces an external specifier to its top-level package name,
+// keeping the scope for scoped packages (e.g. "@org/pkg/sub" -> "@org/pkg")
+func packageName(specifier string) string {
+	specifier = strings.TrimPrefix(specifier, "node:")
+	parts := strings.Split(specifier, "/")
+	if strings.HasPrefix(specifier, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// countExternalPackages tallies external package usage across all nodes,
+// ordered by descending usage count and then by name
+func countExternalPackages(nodesMap map[string]ComponentNode) []ExternalPackage {
+	counts := make(map[string]int)
+	for _, node := range nodesMap {
+		for _, pkg := range node.Externals {
+			counts[pkg]++
+		}
+	}
+
+	packages := make([]ExternalPackage, 0, len(counts))
+	for name, count := range counts {
+		packages = append(packages, ExternalPackage{Name: name, Count: count})
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Count != packages[j].Count {
+			return packages[i].Count > packages[j].Count
+		}
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// parseImportClause records the bindings of an import clause such as
+// "React, { useState as useLocalState }" or "* as utils" on the edge
+func parseImportClause(clause string, edge *ImportEdge) {
+	clause = strings.TrimSpace(clause)
+	clause = strings.TrimPrefix(clause, "type ")
+
+	if match := namespaceImportRegex.FindStringSubmatch(clause); match != nil {
+		edge.Namespace = match[1]
+		clause = strings.Replace(clause, match[0], "", 1)
+	}
+
+	if start := strings.Index(clause, "{"); start >= 0 {
+		end := strings.Index(clause, "}")
+		if end < start {
+			end = len(clause)
+		}
+		for _, specifier := range strings.Split(clause[start+1:end], ",") {
+			fields := strings.Fields(specifier)
+			if len(fields) > 0 && fields[0] == "type" {
+				fields = fields[1:]
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			edge.Named = append(edge.Named, fields[0])
+			if len(fields) == 3 && fields[1] == "as" && fields[2] != fields[0] {
+				if edge.LocalNames == nil {
+					edge.LocalNames = make(map[string]string)
 				}
+				edge.LocalNames[fields[0]] = fields[2]
 			}
+		}
+		clause = clause[:start]
+	}
+
+	defaultName := strings.TrimSpace(strings.Trim(strings.TrimSpace(clause), ","))
+	if defaultName != "" {
+		edge.Default = defaultName
+	}
+}
+
+// localName returns the name an imported symbol is bound to in the importing file
+func (e ImportEdge) localName(imported string) string {
+	if local, ok := e.LocalNames[imported]; ok {
+		return local
+	}
+	return imported
+}
+
+// localBindings returns every local name the edge binds to a symbol of its
+// target: the default import followed by the named imports
+func (e ImportEdge) localBindings() []string {
+	locals := []string{}
+	if e.Default != "" {
+		locals = append(locals, e.Default)
+	}
+	for _, name := range e.Named {
+		locals = append(locals, e.localName(name))
+	}
+	return locals
+}
+
+// edgeTargets returns the target path of every edge
+func edgeTargets(edges []ImportEdge) []string {
+	targets := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		targets = append(targets, edge.Target)
+	}
+	return targets
+}
+
+// resolveImport resolves an import specifier to a path relative to the project root.
+// It returns false for specifiers that look like external modules.
+func resolveImport(src sourceFS, importPath, dir string, rootDir string, aliasConfig AliasConfig, opts ScanOptions) (string, bool) {
+	resolvedPath, ok := resolveImportDir(src, importPath, dir, rootDir, aliasConfig, opts)
+	if !ok {
+		return "", false
+	}
+
+	// Settle on the file the specifier refers to: exact match, added
+	// extension, then directory index
+	fullPath := filepath.Join(rootDir, resolvedPath)
+	if file, ok := platformModuleFile(src, fullPath, opts.Platform); ok {
+		resolvedPath += strings.TrimPrefix(file, fullPath)
+	}
+
+	return resolvedPath, true
+}
+
+// resolveImportDir resolves a specifier to a path relative to rootDir without
+// settling on a file, applying aliases and the external-package heuristics.
+// Templated dynamic imports use it for the directory they glob.
+func resolveImportDir(src sourceFS, importPath, dir string, rootDir string, aliasConfig AliasConfig, opts ScanOptions) (string, bool) {
+	// Configured aliases take precedence over the external-package heuristics
+	// below, even when they point into node_modules
+	isAlias := aliasConfig.isAlias(importPath)
+
+	// Node builtins are never project files, unless a bare name is shadowed by
+	// an alias or a module in a base directory
+	if isNodeBuiltin(importPath) && (strings.HasPrefix(importPath, "node:") ||
+		!(isAlias || aliasConfig.inBaseDir(src, importPath, rootDir))) {
+		return "", false
+	}
+
+	// Skip obvious node_modules imports (packages with @ or no path separators)
+	if !isAlias && (strings.HasPrefix(importPath, "@") || !strings.Contains(importPath, "/")) {
+		// But make an exception for single-word modules in a base directory
+		if !aliasConfig.inBaseDir(src, importPath, rootDir) &&
+			!strings.HasPrefix(importPath, ".") && !strings.HasPrefix(importPath, "/") {
+			return "", false // Skip this import as it's likely an external module
+		}
+	}
+
+	// Resolve the import path using our alias configuration
+	resolvedPath := resolveImportPath(src, importPath, aliasConfig, rootDir, dir)
+
+	// Make path relative to project root
+	relPath, err := filepath.Rel(rootDir, resolvedPath)
+	if err == nil {
+		resolvedPath = relPath
+	}
 
-			imports = append(imports, resolvedPath)
+	// On case-insensitive filesystems, fix up casing that only matches loosely
+	if opts.CaseInsensitive && !moduleExists(src, filepath.Join(rootDir, resolvedPath)) {
+		if folded, ok := foldModulePath(src, rootDir, resolvedPath); ok {
+			resolvedPath = folded
 		}
 	}
 
-	return imports
+	return resolvedPath, true
+}
+
+// nodeBuiltins are the core modules of Node.js
+var nodeBuiltins = map[string]bool{
+	"assert": true, "async_hooks": true, "buffer": true, "child_process": true, "cluster": true,
+	"console": true, "constants": true, "crypto": true, "dgram": true, "diagnostics_channel": true,
+	"dns": true, "domain": true, "events": true, "fs": true, "http": true, "http2": true,
+	"https": true, "inspector": true, "module": true, "net": true, "os": true, "path": true,
+	"perf_hooks": true, "process": true, "punycode": true, "querystring": true, "readline": true,
+	"repl": true, "stream": true, "string_decoder": true, "sys": true, "timers": true,
+	"tls": true, "trace_events": true, "tty": true, "url": true, "util": true, "v8": true,
+	"vm": true, "wasi": true, "worker_threads": true, "zlib": true,
+}
+
+// isNodeBuiltin reports whether a specifier names a Node.js core module, such
+// as "fs", "fs/promises" or anything with the "node:" scheme
+func isNodeBuiltin(specifier string) bool {
+	if strings.HasPrefix(specifier, "node:") {
+		return true
+	}
+	name, _, _ := strings.Cut(specifier, "/")
+	return nodeBuiltins[name]
 }
 
 // buildRelationships establishes connections between components
@@ -299,6 +1344,45 @@ func buildRelationships(project *Project) {
 			}
 		}
 	}
+
+	// Record how each importer references the nodes it imports
+	for id, node := range project.NodesMap {
+		for _, edge := range node.ImportEdges {
+			importedNode, exists := project.NodesMap[edge.Target]
+			if !exists || edge.Kind != "import" {
+				continue
+			}
+			importedNode.ImportedByDetails = append(importedNode.ImportedByDetails, ImporterRef{
+				Importer:  id,
+				Default:   edge.Default != "",
+				Named:     edge.Named,
+				Namespace: edge.Namespace != "",
+			})
+			project.NodesMap[edge.Target] = importedNode
+		}
+	}
+}
+
+// markDevEdges flags edges whose source or target is a test or story file
+// and tallies production versus dev-only and out-of-root edge counts
+func markDevEdges(project *Project) {
+	for id, node := range project.NodesMap {
+		for i, edge := range node.ImportEdges {
+			target, exists := project.NodesMap[edge.Target]
+			node.ImportEdges[i].Dev = node.Type == "test" || (exists && target.Type == "test")
+
+			project.Stats.ImportEdges++
+			if node.ImportEdges[i].Dev {
+				project.Stats.DevImportEdges++
+			} else {
+				project.Stats.ProductionImportEdges++
+			}
+			if edge.OutOfRoot {
+				project.Stats.OutOfRootEdges++
+			}
+		}
+		project.NodesMap[id] = node
+	}
 }
 
 // buildTree constructs a hierarchical tree based on directory structure
@@ -308,11 +1392,92 @@ func buildTree(project *Project) {
 
 	for _, node := range project.NodesMap {
 		dir := filepath.Dir(node.Path)
+
+		// Prune everything outside the configured tree root
+		if project.TreeRoot != "" && dir != project.TreeRoot &&
+			!strings.HasPrefix(dir, project.TreeRoot+string(filepath.Separator)) {
+			continue
+		}
+
 		dirNodes[dir] = append(dirNodes[dir], node)
 	}
 
 	// Build tree recursively
-	buildTreeRecursive(&project.Root, "", dirNodes)
+	buildTreeRecursive(&project.Root, project.TreeRoot, dirNodes)
+
+	// Files from source roots outside the scanned directory hang off the root
+	// under their first directory past the leading "..", e.g. ../shared
+	outside := make(map[string]bool)
+	for dir := range dirNodes {
+		if !isOutsideRoot(dir) {
+			continue
+		}
+		segments := strings.Split(dir, string(filepath.Separator))
+		for i, segment := range segments {
+			if segment != ".." {
+				outside[filepath.Join(segments[:i+1]...)] = true
+				break
+			}
+		}
+	}
+	for _, dir := range sortedKeys(outside) {
+		dirNode := ComponentNode{ID: dir, Name: filepath.Base(dir), Path: dir, Type: "directory", Children: []ComponentNode{}}
+		buildTreeRecursive(&dirNode, dir, dirNodes)
+		project.Root.Children = append(project.Root.Children, dirNode)
+	}
+}
+
+// manifestName returns the "name" from the package.json in rootDir or its
+// nearest ancestor that has one
+func manifestName(src sourceFS, rootDir string) string {
+	dir := rootDir
+	if src.fsys == nil {
+		if abs, err := filepath.Abs(rootDir); err == nil {
+			dir = abs
+		}
+	}
+
+	for {
+		if data, err := src.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+			var manifest struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(data, &manifest) == nil && manifest.Name != "" {
+				return manifest.Name
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolveTreeRoot determines the directory, relative to the scanned root, at
+// which the tree should start: "" or "scanned" for the scanned directory,
+// "source" for the detected source root, or any other value as a subpath
+func resolveTreeRoot(strategy string, project Project, aliasConfig AliasConfig) string {
+	switch strategy {
+	case "", "scanned":
+		return ""
+	case "source":
+		if baseURL := filepath.Clean(aliasConfig.BaseURL); aliasConfig.BaseURL != "" && baseURL != "." {
+			return baseURL
+		}
+		for id := range project.NodesMap {
+			if strings.HasPrefix(id, "src"+string(filepath.Separator)) {
+				return "src"
+			}
+		}
+		return ""
+	default:
+		subpath := filepath.Clean(filepath.FromSlash(strategy))
+		if subpath == "." {
+			return ""
+		}
+		return subpath
+	}
 }
 
 // buildTreeRecursive is a helper function for buildTree
@@ -346,81 +1511,98 @@ func buildTreeRecursive(parent *ComponentNode, dir string, dirNodes map[string][
 	}
 }
 
-func ConvertToUnixPath(path string) string {
-	return strings.ReplaceAll(path, "\\", "/")
-}
-
-func ConvertProjectPathsToUnix(project *Project) {
-	// Convert root paths
-	project.Root.Path = ConvertToUnixPath(project.Root.Path)
-
-	// Convert all files paths
-	for i, filePath := range project.Files {
-		project.Files[i] = ConvertToUnixPath(filePath)
+// rebasePaths rewrites node IDs and paths, which are relative to rootDir, to
+// be relative to the working directory ("cwd") or absolute ("absolute")
+func rebasePaths(project *Project, rootDir, base string) error {
+	if base == "" || base == "root" {
+		return nil
 	}
 
-	// Create a new map with converted keys and values
-	newNodesMap := make(map[string]ComponentNode)
-	for id, node := range project.NodesMap {
-		// Convert node paths
-		node.ID = ConvertToUnixPath(node.ID)
-		node.Path = ConvertToUnixPath(node.Path)
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return err
+	}
 
-		// Convert imports paths
-		for i, importPath := range node.Imports {
-			node.Imports[i] = ConvertToUnixPath(importPath)
+	var mapPath func(string) string
+	switch base {
+	case "absolute":
+		mapPath = func(p string) string {
+			if filepath.IsAbs(p) {
+				return p
+			}
+			return filepath.Join(absRoot, p)
 		}
-
-		// Convert importedBy paths
-		for i, importedBy := range node.ImportedBy {
-			node.ImportedBy[i] = ConvertToUnixPath(importedBy)
+	case "cwd":
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
 		}
-
-		// Convert children paths recursively
-		convertChildrenPaths(&node)
-
-		// Add to new map with Unix path as key
-		newNodesMap[ConvertToUnixPath(id)] = node
+		mapPath = func(p string) string {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(absRoot, p)
+			}
+			if rel, err := filepath.Rel(cwd, p); err == nil {
+				return rel
+			}
+			return p
+		}
+	default:
+		return fmt.Errorf("unknown path base %q", base)
 	}
 
-	// Replace the original map
-	project.NodesMap = newNodesMap
+	projectRemapper{path: mapPath}.apply(project)
+	return nil
 }
 
-func convertChildrenPaths(node *ComponentNode) {
-	for i := range node.Children {
-		child := &node.Children[i]
-		child.ID = ConvertToUnixPath(child.ID)
-		child.Path = ConvertToUnixPath(child.Path)
-
-		for j, importPath := range child.Imports {
-			child.Imports[j] = ConvertToUnixPath(importPath)
-		}
+func ConvertToUnixPath(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
 
-		for j, importedBy := range child.ImportedBy {
-			child.ImportedBy[j] = ConvertToUnixPath(importedBy)
-		}
+func ConvertProjectPathsToUnix(project *Project) {
+	// Convert root paths
+	project.Root.Path = ConvertToUnixPath(project.Root.Path)
 
-		convertChildrenPaths(child)
-	}
+	// Convert every node ID and path, including the tree
+	projectRemapper{path: ConvertToUnixPath}.apply(project)
 }
 
 // GetProjectJSON returns project data as JSON and saves it to disk
 func GetProjectJSON(rootDir string) (string, error) {
-	project, err := ScanProject(rootDir)
+	return GetProjectJSONWithOptions(rootDir, DefaultScanOptions())
+}
+
+// GetProjectJSONWithOptions scans with the given options and returns the project as JSON
+func GetProjectJSONWithOptions(rootDir string, opts ScanOptions) (string, error) {
+	project, err := ScanProjectWithOptions(rootDir, opts)
 	if err != nil {
 		return "", err
 	}
 
+	return projectJSON(rootDir, project, opts.JSONKeys, !opts.NoSave)
+}
+
+// projectJSON converts a scanned project to JSON with the given key style and,
+// if save is set, saves a copy to disk. The saved copy always uses camelCase keys.
+func projectJSON(rootDir string, project Project, keys string, save bool) (string, error) {
 	ConvertProjectPathsToUnix(&project)
 
-	jsonData, err := json.MarshalIndent(project, "", "  ")
+	var jsonData []byte
+	var err error
+	if keys == "snake" {
+		jsonData, err = marshalSnakeCase(project)
+	} else {
+		jsonData, err = json.MarshalIndent(project, "", "  ")
+	}
 	if err != nil {
 		return "", err
 	}
 
+	if !save {
+		return string(jsonData), nil
+	}
+
 	// Save to file in $HOME/.local/reactviz/
-	err = saveProjectJSON(rootDir, jsonData)
+	err = saveProjectJSON(rootDir, project)
 	if err != nil {
 		return "", err
 	}
@@ -428,8 +1610,48 @@ func GetProjectJSON(rootDir string) (string, error) {
 	return string(jsonData), nil
 }
 
+// WriteProjectJSON streams the project as indented JSON to w without
+// buffering the whole document in memory
+func WriteProjectJSON(w io.Writer, project Project) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(project)
+}
+
+// ndjsonMetadata is the leading line of WriteNodesNDJSON output
+type ndjsonMetadata struct {
+	Root         string       `json:"root"`
+	Stats        ProjectStats `json:"stats"`
+	EntryPoints  []string     `json:"entryPoints,omitempty"`
+	TreeRoot     string       `json:"treeRoot,omitempty"`
+	ScanWarnings []string     `json:"scanWarnings,omitempty"`
+}
+
+// WriteNodesNDJSON writes the project as newline-delimited JSON: a metadata
+// line with the root and stats, then one line per node in ID order
+func WriteNodesNDJSON(w io.Writer, project Project) error {
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(ndjsonMetadata{
+		Root:         project.Root.Path,
+		Stats:        project.Stats,
+		EntryPoints:  project.EntryPoints,
+		TreeRoot:     project.TreeRoot,
+		ScanWarnings: project.ScanWarnings,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range sortedNodeIDs(project) {
+		if err := encoder.Encode(project.NodesMap[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // saveProjectJSON saves the project JSON to a file
-func saveProjectJSON(rootDir string, jsonData []byte) error {
+func saveProjectJSON(rootDir string, project Project) error {
 	// Get project name from root directory
 	projectName := filepath.Base(rootDir)
 
@@ -456,5 +1678,15 @@ func saveProjectJSON(rootDir string, jsonData []byte) error {
 	filePath := filepath.Join(targetDir, filename)
 
 	// Write file
-	return os.WriteFile(filePath, jsonData, 0644)
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteProjectJSON(file, project); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
 }
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// scanFiles scans an in-memory project made of files keyed by their
+// slash-separated path relative to the project root
+func scanFiles(t *testing.T, files map[string]string, opts ScanOptions) Project {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	opts.Quiet = true
+	project, err := ScanProjectFS(fsys, "app", opts)
+	if err != nil {
+		t.Fatalf("ScanProjectFS: %v", err)
+	}
+	return project
+}
+
+// writeFiles writes files keyed by slash-separated path under a new temporary
+// directory and returns it, for features that need a real disk
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// scanDir scans a project on disk without logging
+func scanDir(t *testing.T, dir string, opts ScanOptions) Project {
+	t.Helper()
+	opts.Quiet = true
+	project, err := ScanProjectWithOptions(dir, opts)
+	if err != nil {
+		t.Fatalf("ScanProjectWithOptions: %v", err)
+	}
+	return project
+}
+
+// nodeAt returns the node with the slash-separated ID, failing the test if
+// the project has none
+func nodeAt(t *testing.T, project Project, id string) ComponentNode {
+	t.Helper()
+	node, ok := project.NodesMap[filepath.FromSlash(id)]
+	if !ok {
+		t.Fatalf("no node %s among %v", id, sortedNodeIDs(project))
+	}
+	return node
+}
+
+// edgeTo returns the edge from one node to another, both given as
+// slash-separated IDs
+func edgeTo(t *testing.T, project Project, from, to string) (ImportEdge, bool) {
+	t.Helper()
+	for _, edge := range nodeAt(t, project, from).ImportEdges {
+		if edge.Target == filepath.FromSlash(to) {
+			return edge, true
+		}
+	}
+	return ImportEdge{}, false
+}
+
+func TestClassifyCustom(t *testing.T) {
+	files := map[string]string{
+		"src/services/api.ts":    "export const fetchUser = () => fetch('/user')\n",
+		"src/utils/format.ts":    "export const format = (s) => s.trim()\n",
+		"src/components/Nav.tsx": "import React from 'react'\nexport const Nav = () => <nav />\n",
+	}
+	services := func(path, content string) (string, bool) {
+		if strings.HasPrefix(filepath.ToSlash(path), "src/services/") {
+			return "service", true
+		}
+		return "", false
+	}
+
+	project := scanFiles(t, files, ScanOptions{Classify: services})
+	for id, want := range map[string]string{
+		"src/services/api.ts":    "service",
+		"src/utils/format.ts":    "util",
+		"src/components/Nav.tsx": "component",
+	} {
+		if got := nodeAt(t, project, id).Type; got != want {
+			t.Errorf("%s: type = %q, want %q", id, got, want)
+		}
+	}
+
+	// Without the classifier the built-in heuristics decide
+	project = scanFiles(t, files, ScanOptions{})
+	if got := nodeAt(t, project, "src/services/api.ts").Type; got != "util" {
+		t.Errorf("default type = %q, want util", got)
+	}
+}
+
+func TestWorkerURLEdge(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/App.tsx":   "const worker = new Worker(new URL('./worker.ts', import.meta.url), { type: 'module' })\n",
+		"src/worker.ts": "self.onmessage = (e) => self.postMessage(e.data)\n",
+	}, ScanOptions{})
+
+	edge, ok := edgeTo(t, project, "src/App.tsx", "src/worker.ts")
+	if !ok {
+		t.Fatal("no edge from App.tsx to worker.ts")
+	}
+	if edge.Kind != "worker" {
+		t.Errorf("kind = %q, want worker", edge.Kind)
+	}
+}
+
+func TestImportEqualsRequire(t *testing.T) {
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"src/legacy.ts": "import Foo = require('./foo')\nimport fs = require('fs')\nexport = Foo\n",
+			"src/foo.ts":    "class Foo {}\nexport = Foo\n",
+		}, ScanOptions{UseAST: useAST})
+
+		edge, ok := edgeTo(t, project, "src/legacy.ts", "src/foo.ts")
+		if !ok {
+			t.Fatalf("UseAST=%v: no edge from legacy.ts to foo.ts", useAST)
+		}
+		if edge.Kind != "import" || edge.Default != "Foo" {
+			t.Errorf("UseAST=%v: edge = %+v, want an import binding Foo", useAST, edge)
+		}
+		if node := nodeAt(t, project, "src/legacy.ts"); len(node.ImportEdges) != 1 || !containsString(node.Externals, "fs") {
+			t.Errorf("UseAST=%v: edges = %+v, externals = %v", useAST, node.ImportEdges, node.Externals)
+		}
+	}
+}
+
+func TestViteQuerySuffixes(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/App.jsx":     "import shader from './shader.glsl?raw'\nimport Worker from './worker?worker'\nimport logo from './logo.svg?url'\n",
+		"src/shader.glsl": "void main() {}\n",
+		"src/worker.js":   "self.onmessage = () => {}\n",
+		"src/logo.svg":    "<svg />\n",
+	}, ScanOptions{})
+
+	tests := []struct {
+		target, query, kind string
+	}{
+		{"src/shader.glsl", "raw", "import"},
+		{"src/worker.js", "worker", "worker"},
+		{"src/logo.svg", "url", "import"},
+	}
+	for _, tt := range tests {
+		edge, ok := edgeTo(t, project, "src/App.jsx", tt.target)
+		if !ok {
+			t.Errorf("no edge to %s; imports %v", tt.target, nodeAt(t, project, "src/App.jsx").Imports)
+			continue
+		}
+		if edge.Query != tt.query || edge.Kind != tt.kind {
+			t.Errorf("edge to %s: query %q kind %q, want %q and %q", tt.target, edge.Query, edge.Kind, tt.query, tt.kind)
+		}
+	}
+}
+
+// treeFiles returns the slash-separated IDs of the file nodes in a tree
+func treeFiles(node ComponentNode) []string {
+	files := []string{}
+	for _, child := range node.Children {
+		if child.Type == "directory" {
+			files = append(files, treeFiles(child)...)
+		} else {
+			files = append(files, filepath.ToSlash(child.ID))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestTreeRoot(t *testing.T) {
+	files := map[string]string{
+		"scripts/build.js":      "export const build = () => {}\n",
+		"src/App.jsx":           "export default function App() { return <div /> }\n",
+		"src/features/Cart.jsx": "export default function Cart() { return <div /> }\n",
+	}
+
+	tests := []struct {
+		strategy string
+		name     string
+		files    []string
+	}{
+		{"", "app", []string{"scripts/build.js", "src/App.jsx", "src/features/Cart.jsx"}},
+		{"scanned", "app", []string{"scripts/build.js", "src/App.jsx", "src/features/Cart.jsx"}},
+		{"source", "src", []string{"src/App.jsx", "src/features/Cart.jsx"}},
+		{"src/features", "features", []string{"src/features/Cart.jsx"}},
+	}
+	for _, tt := range tests {
+		project := scanFiles(t, files, ScanOptions{TreeRoot: tt.strategy})
+		if project.Root.Name != tt.name {
+			t.Errorf("TreeRoot %q: root name = %q, want %q", tt.strategy, project.Root.Name, tt.name)
+		}
+		if got := treeFiles(project.Root); !reflect.DeepEqual(got, tt.files) {
+			t.Errorf("TreeRoot %q: tree files = %v, want %v", tt.strategy, got, tt.files)
+		}
+		if len(project.NodesMap) != len(files) {
+			t.Errorf("TreeRoot %q: NodesMap has %d nodes, want %d", tt.strategy, len(project.NodesMap), len(files))
+		}
+	}
+}
+
+func TestPathBase(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"src/App.jsx":    "import Button from './Button'\nexport default function App() { return <Button /> }\n",
+		"src/Button.jsx": "export default function Button() { return <button /> }\n",
+	})
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCwd, err := filepath.Rel(cwd, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		base   string
+		prefix string
+	}{
+		{"", ""},
+		{"root", ""},
+		{"cwd", fromCwd},
+		{"absolute", root},
+	}
+	for _, tt := range tests {
+		project := scanDir(t, root, ScanOptions{PathBase: tt.base})
+		app := filepath.Join(tt.prefix, "src", "App.jsx")
+		button := filepath.Join(tt.prefix, "src", "Button.jsx")
+
+		node, ok := project.NodesMap[app]
+		if !ok {
+			t.Errorf("PathBase %q: no node %s among %v", tt.base, app, sortedNodeIDs(project))
+			continue
+		}
+		if node.Path != app || !reflect.DeepEqual(node.Imports, []string{button}) {
+			t.Errorf("PathBase %q: path %s, imports %v", tt.base, node.Path, node.Imports)
+		}
+		if importedBy := project.NodesMap[button].ImportedBy; !reflect.DeepEqual(importedBy, []string{app}) {
+			t.Errorf("PathBase %q: Button.jsx imported by %v", tt.base, importedBy)
+		}
+		if files := treeFiles(project.Root); !reflect.DeepEqual(files, []string{filepath.ToSlash(app), filepath.ToSlash(button)}) {
+			t.Errorf("PathBase %q: tree files %v", tt.base, files)
+		}
+	}
+
+	if _, err := ScanProjectWithOptions(root, ScanOptions{PathBase: "home", Quiet: true}); err == nil {
+		t.Error("unknown PathBase was accepted")
+	}
+}
+
+func TestCSSModuleEdge(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/Button.jsx":        "import styles from './Button.module.css'\nimport './global.css'\nexport default function Button() { return <button className={styles.root} /> }\n",
+		"src/Button.module.css": ".root { color: red; }\n",
+		"src/global.css":        "body { margin: 0; }\n",
+	}, ScanOptions{})
+
+	edge, ok := edgeTo(t, project, "src/Button.jsx", "src/Button.module.css")
+	if !ok {
+		t.Fatalf("Button.jsx imports %v, want src/Button.module.css", nodeAt(t, project, "src/Button.jsx").Imports)
+	}
+	if edge.Kind != "styleModule" || edge.Default != "styles" {
+		t.Errorf("edge = %+v, want a styleModule edge binding styles", edge)
+	}
+}
+
+func TestScanLogger(t *testing.T) {
+	files := map[string]string{
+		".reactviz.json": `{"hide": ["src/Missing.jsx"]}`,
+		"src/App.jsx":    "export default function App() { return <div /> }\n",
+	}
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logged, nil))
+	project, err := ScanProjectFS(fsys, "app", ScanOptions{Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(project.ScanWarnings) != 1 || !strings.Contains(project.ScanWarnings[0], "src/Missing.jsx") {
+		t.Fatalf("warnings = %q, want the unknown sidecar entry", project.ScanWarnings)
+	}
+	if !strings.Contains(logged.String(), "level=WARN") || !strings.Contains(logged.String(), "src/Missing.jsx") {
+		t.Errorf("logger output = %q, want the warning", logged.String())
+	}
+}
+
+func TestRootIndexName(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"index.tsx":            "import App from './src/App'\nexport default App\n",
+		"src/App.tsx":          "export default function App() { return <div /> }\n",
+		"src/widgets/index.ts": "export const widgets = []\n",
+	}, ScanOptions{})
+
+	for id, want := range map[string]string{
+		"index.tsx":            "app/index",
+		"src/App.tsx":          "App",
+		"src/widgets/index.ts": "widgets/index",
+	} {
+		if got := nodeAt(t, project, id).Name; got != want {
+			t.Errorf("%s: name = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestTemplateImportResolution(t *testing.T) {
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"tsconfig.json":       `{"compilerOptions": {"baseUrl": ".", "paths": {"@/*": ["src/*"]}}}`,
+			"src/i18n/load.ts":    "export const load = (lang) => import(`@/locales/${lang}.json`)\nexport const page = (name) => import(`../pages/${name}`)\nexport const shared = (name) => import(`../../../shared/${name}.ts`)\nexport const icon = (name) => import(`lucide-react/icons/${name}`)\n",
+			"src/locales/en.json": "{}\n",
+			"src/pages/Home.tsx":  "export default function Home() { return <main /> }\n",
+		}, ScanOptions{UseAST: useAST})
+
+		tests := []struct {
+			target, pattern string
+			outOfRoot       bool
+		}{
+			{"src/locales", "@/locales/*.json", false},
+			{"src/pages", "../pages/*", false},
+			{"../shared", "../../../shared/*.ts", true},
+		}
+		for _, tt := range tests {
+			edge, ok := edgeTo(t, project, "src/i18n/load.ts", tt.target)
+			if !ok {
+				t.Errorf("UseAST=%v: load.ts imports %v, want %s", useAST, nodeAt(t, project, "src/i18n/load.ts").Imports, tt.target)
+				continue
+			}
+			if edge.Kind != "dynamicGlob" || edge.Pattern != tt.pattern || edge.OutOfRoot != tt.outOfRoot {
+				t.Errorf("UseAST=%v: edge = %+v", useAST, edge)
+			}
+		}
+		if externals := nodeAt(t, project, "src/i18n/load.ts").Externals; !reflect.DeepEqual(externals, []string{"lucide-react"}) {
+			t.Errorf("UseAST=%v: externals = %v, want [lucide-react]", useAST, externals)
+		}
+	}
+}
+
+func TestScanProjectFS(t *testing.T) {
+	// Nothing named app exists on disk, so every read must go through fsys
+	fsys := fstest.MapFS{
+		"package.json":       {Data: []byte(`{"name": "shop"}`)},
+		"src/App.tsx":        {Data: []byte("import { Cart } from './Cart'\nexport default function App() { return <Cart /> }\n")},
+		"src/Cart.tsx":       {Data: []byte("export const Cart = () => <div />\n")},
+		"node_modules/x.js":  {Data: []byte("module.exports = {}\n")},
+		"src/notes/todo.txt": {Data: []byte("import x from './nothing'\n")},
+	}
+	project, err := ScanProjectFS(fsys, "app", ScanOptions{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sortedNodeIDs(project), []string{filepath.Join("src", "App.tsx"), filepath.Join("src", "Cart.tsx")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes = %v, want %v", got, want)
+	}
+	if _, ok := edgeTo(t, project, "src/App.tsx", "src/Cart.tsx"); !ok {
+		t.Error("no edge from App.tsx to Cart.tsx")
+	}
+	if importedBy := nodeAt(t, project, "src/Cart.tsx").ImportedBy; !reflect.DeepEqual(importedBy, []string{filepath.Join("src", "App.tsx")}) {
+		t.Errorf("Cart.tsx imported by %v", importedBy)
+	}
+}
+
+func TestImportLines(t *testing.T) {
+	content := "import React from 'react'\n\nimport {\n  Card,\n  CardBody,\n} from './Card'\n// import Old from './Card'\nconst Lazy = lazy(() =>\n  import('./Card')\n)\nexport * from './Card'\n"
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"src/App.jsx":  content,
+			"src/Card.jsx": "export const Card = () => <div />\nexport const CardBody = () => <div />\n",
+		}, ScanOptions{UseAST: useAST})
+
+		edges := nodeAt(t, project, "src/App.jsx").ImportEdges
+		if len(edges) != 3 {
+			t.Errorf("UseAST=%v: edges = %+v, want one per statement", useAST, edges)
+		}
+		lines := map[string]int{}
+		for _, edge := range edges {
+			lines[edge.Kind] = edge.Line
+		}
+		if want := map[string]int{"import": 3, "lazy": 9, "reexport": 11}; !reflect.DeepEqual(lines, want) {
+			t.Errorf("UseAST=%v: lines by kind = %v, want %v", useAST, lines, want)
+		}
+	}
+}
+
+func TestReducerState(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/logic/counterReducer.ts": `export type CounterState = { count: number }
+export type CounterAction = { type: 'increment' } | { type: 'reset'; payload: number }
+
+export function counterReducer(state: CounterState, action: CounterAction): CounterState {
+  switch (action.type) {
+    case 'increment':
+      return { count: state.count + 1 }
+    case 'reset':
+      return { count: action.payload }
+  }
+}
+`,
+		"src/logic/math.ts": "export const clamp = (n: number, min: number, max: number) => Math.min(Math.max(n, min), max)\n",
+	}, ScanOptions{})
+
+	for id, want := range map[string]string{
+		"src/logic/counterReducer.ts": "state",
+		"src/logic/math.ts":           "util",
+	} {
+		if got := nodeAt(t, project, id).Type; got != want {
+			t.Errorf("%s: type = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestRootNameFromManifest(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json":           `{"name": "my-app", "private": true}`,
+		"src/App.jsx":            "export default function App() { return <div /> }\n",
+		"src/admin/package.json": `{"private": true}`,
+		"src/admin/Panel.jsx":    "export default function Panel() { return <div /> }\n",
+	})
+
+	tests := []struct {
+		dir  string
+		opts ScanOptions
+		want string
+	}{
+		{"src", ScanOptions{}, "my-app"},
+		{"src/admin", ScanOptions{}, "my-app"}, // a manifest without a name is skipped
+		{"src", ScanOptions{ProjectName: "Storefront"}, "Storefront"},
+	}
+	for _, tt := range tests {
+		project := scanDir(t, filepath.Join(dir, filepath.FromSlash(tt.dir)), tt.opts)
+		if project.Root.Name != tt.want {
+			t.Errorf("scanning %s with %+v: root name = %q, want %q", tt.dir, tt.opts, project.Root.Name, tt.want)
+		}
+	}
+}
+
+func TestRenamedDefaultImportRender(t *testing.T) {
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"src/Button.tsx": "export default function Button() { return <button /> }\n",
+			"src/App.tsx":    "import MyButton from './Button'\nexport const App = () => <div><MyButton /><MyButton>ok</MyButton></div>\n",
+		}, ScanOptions{UseAST: useAST})
+
+		edge, ok := edgeTo(t, project, "src/App.tsx", "src/Button.tsx")
+		if !ok {
+			t.Fatalf("UseAST=%v: no edge from App.tsx to Button.tsx", useAST)
+		}
+		if edge.Default != "MyButton" || edge.RenderCount != 2 {
+			t.Errorf("UseAST=%v: edge = %+v, want MyButton rendered twice", useAST, edge)
+		}
+	}
+}
+
+func TestNodeBuiltinsAreExternal(t *testing.T) {
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"src/server.ts": "import { readFile } from 'node:fs'\nimport crypto from 'crypto'\nimport { join } from 'path/posix'\nimport { hash } from './hash'\n",
+			"src/hash.ts":   "export const hash = (s) => s\n",
+		}, ScanOptions{UseAST: useAST})
+
+		node := nodeAt(t, project, "src/server.ts")
+		if !reflect.DeepEqual(node.Imports, []string{filepath.Join("src", "hash.ts")}) {
+			t.Errorf("UseAST=%v: imports = %v, want only hash.ts", useAST, node.Imports)
+		}
+		// node: specifiers are reported by module name
+		for _, builtin := range []string{"fs", "crypto", "path"} {
+			if !containsString(node.Externals, builtin) {
+				t.Errorf("UseAST=%v: externals = %v, want %s", useAST, node.Externals, builtin)
+			}
+		}
+	}
+}
+
+func TestRouteConfigLazyImports(t *testing.T) {
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, map[string]string{
+			"src/routes.ts": `export const routes = [
+  { path: '/', component: () => import('./pages/Home') },
+  {
+    path: '/settings',
+    // a comment between keys
+    component: () => import('./pages/Settings'),
+  },
+  { path: "/about", loader: fetchAbout, component: () => import("./pages/About") },
+]
+`,
+			"src/pages/Home.tsx":     "export default function Home() { return <main /> }\n",
+			"src/pages/Settings.tsx": "export default function Settings() { return <main /> }\n",
+			"src/pages/About.tsx":    "export default function About() { return <main /> }\n",
+		}, ScanOptions{UseAST: useAST})
+
+		for target, route := range map[string]string{
+			"src/pages/Home.tsx":     "/",
+			"src/pages/Settings.tsx": "/settings",
+			"src/pages/About.tsx":    "/about",
+		} {
+			edge, ok := edgeTo(t, project, "src/routes.ts", target)
+			if !ok {
+				t.Errorf("UseAST=%v: routes.ts imports %v, want %s", useAST, nodeAt(t, project, "src/routes.ts").Imports, target)
+				continue
+			}
+			if edge.Kind != "lazy" || edge.Route != route {
+				t.Errorf("UseAST=%v: edge to %s = %+v, want a lazy edge for %s", useAST, target, edge, route)
+			}
+		}
+	}
+}
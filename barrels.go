@@ -0,0 +1,443 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExportAlias maps a name exported by a file to the name it has in the module it comes from
+type ExportAlias struct {
+	Local    string `json:"local"`    // name in the source module ("default" for its default export)
+	Exported string `json:"exported"` // name the re-exporting file exposes
+}
+
+// reexportRegex matches named re-exports such as export { Button as PrimaryButton } from './Button'
+var reexportRegex = regexp.MustCompile(`export\s+(?:type\s+)?\{([^}]*)\}\s*from\s+['"]([^'"]+)['"]`)
+
+// starReexportRegex matches star re-exports such as export * from './Button' or export * as ui from './ui'
+var starReexportRegex = regexp.MustCompile(`export\s+\*\s+(?:as\s+([\w$]+)\s+)?from\s+['"]([^'"]+)['"]`)
+
+// exportDeclarationRegex matches exported declarations such as export const Button or export default function
+var exportDeclarationRegex = regexp.MustCompile(`export\s+(default\s+)?(?:declare\s+)?(?:(?:async\s+)?function\*?|class|const|let|var|type|interface|enum|abstract\s+class)?\s*([\w$]*)`)
+
+// exportListRegex matches local export lists such as export { Button, Card as Tile }
+var exportListRegex = regexp.MustCompile(`export\s+(?:type\s+)?\{([^}]*)\}`)
+
+// exportDefaultIdentifierRegex matches a default export of a bare binding, e.g. export default Button;
+var exportDefaultIdentifierRegex = regexp.MustCompile(`(?m)export\s+default\s+([A-Za-z_$][\w$]*)\s*;?\s*$`)
+
+// findExports returns the sorted names a file exports, including re-exports
+func findExports(content string) []string {
+	names := make(map[string]bool)
+
+	for _, match := range exportDeclarationRegex.FindAllStringSubmatch(content, -1) {
+		switch {
+		case match[1] != "":
+			names["default"] = true
+		case match[2] != "":
+			names[match[2]] = true
+		}
+	}
+	for _, match := range exportListRegex.FindAllStringSubmatch(content, -1) {
+		for _, alias := range parseExportList(match[1]) {
+			names[alias.Exported] = true
+		}
+	}
+	for _, match := range starReexportRegex.FindAllStringSubmatch(content, -1) {
+		if match[1] != "" {
+			names[match[1]] = true
+		}
+	}
+
+	return sortedKeys(names)
+}
+
+// sortedKeys returns the keys of a set in sorted order, or nil if it is empty
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseExportList parses the body of an export { ... } clause
+func parseExportList(list string) []ExportAlias {
+	aliases := []ExportAlias{}
+	for _, specifier := range strings.Split(list, ",") {
+		fields := strings.Fields(specifier)
+		if len(fields) > 0 && fields[0] == "type" {
+			fields = fields[1:]
+		}
+		switch {
+		case len(fields) == 1:
+			aliases = append(aliases, ExportAlias{Local: fields[0], Exported: fields[0]})
+		case len(fields) == 3 && fields[1] == "as":
+			aliases = append(aliases, ExportAlias{Local: fields[0], Exported: fields[2]})
+		}
+	}
+	return aliases
+}
+
+// recordPassThroughExports notes imported bindings the file exports again,
+// as in import Button from './Button'; export default Button, by adding them
+// to the import edge's Reexports so barrel flattening can see through the file
+func recordPassThroughExports(content string, edges []ImportEdge) {
+	exported := []ExportAlias{}
+	for _, match := range exportDefaultIdentifierRegex.FindAllStringSubmatch(content, -1) {
+		exported = append(exported, ExportAlias{Local: match[1], Exported: "default"})
+	}
+	for _, loc := range exportListRegex.FindAllStringSubmatchIndex(content, -1) {
+		if strings.HasPrefix(strings.TrimSpace(content[loc[1]:]), "from") {
+			continue // export { ... } from is a re-export edge of its own
+		}
+		exported = append(exported, parseExportList(content[loc[2]:loc[3]])...)
+	}
+
+	for i, edge := range edges {
+		if edge.Kind != "import" {
+			continue
+		}
+		imported := make(map[string]string) // local binding -> imported name
+		if edge.Default != "" {
+			imported[edge.Default] = "default"
+		}
+		for _, name := range edge.Named {
+			imported[edge.localName(name)] = name
+		}
+		for _, alias := range exported {
+			if name, ok := imported[alias.Local]; ok {
+				edges[i].Reexports = append(edges[i].Reexports, ExportAlias{Local: name, Exported: alias.Exported})
+			}
+		}
+	}
+}
+
+// resolveReexport follows the re-exports of symbol from the node at id down to
+// the file that defines it, returning that file and the symbol's original name
+func resolveReexport(project *Project, id, symbol string, visited map[string]bool) (string, string, bool) {
+	if visited[id] {
+		return "", "", false
+	}
+	visited[id] = true
+
+	for _, edge := range project.NodesMap[id].ImportEdges {
+		for _, alias := range edge.Reexports {
+			if alias.Exported != symbol || alias.Local == "*" {
+				continue
+			}
+			if _, exists := project.NodesMap[edge.Target]; !exists {
+				return "", "", false
+			}
+			// The target may itself be a barrel
+			if target, original, ok := resolveReexport(project, edge.Target, alias.Local, visited); ok {
+				return target, original, true
+			}
+			return edge.Target, alias.Local, true
+		}
+	}
+
+	// Fall back to export * from, which forwards every named export
+	if symbol == "default" {
+		return "", "", false
+	}
+	for _, edge := range project.NodesMap[id].ImportEdges {
+		if edge.Kind != "reexport" || !edge.Star {
+			continue
+		}
+		if target, original, ok := resolveReexport(project, edge.Target, symbol, visited); ok {
+			return target, original, true
+		}
+		if target, exists := project.NodesMap[edge.Target]; exists && containsString(target.Exports, symbol) {
+			return edge.Target, symbol, true
+		}
+	}
+
+	return "", "", false
+}
+
+// isBarrel reports whether a node only re-exports other modules
+func isBarrel(node ComponentNode) bool {
+	if len(node.ImportEdges) == 0 {
+		return false
+	}
+	for _, edge := range node.ImportEdges {
+		if edge.Kind != "reexport" {
+			return false
+		}
+	}
+	return true
+}
+
+// starMembers returns the modules a star re-export of id ultimately exposes,
+// fanning out through nested barrels. Visited barrels are not expanded again.
+func starMembers(project *Project, id string, visited map[string]bool) []string {
+	node, exists := project.NodesMap[id]
+	if !exists || !isBarrel(node) {
+		return []string{id}
+	}
+	if visited[id] {
+		return nil
+	}
+	visited[id] = true
+
+	members := []string{}
+	for _, edge := range node.ImportEdges {
+		if _, exists := project.NodesMap[edge.Target]; !exists {
+			continue
+		}
+		if edge.Star {
+			members = append(members, starMembers(project, edge.Target, visited)...)
+		} else {
+			members = append(members, edge.Target)
+		}
+	}
+	return members
+}
+
+// expandStarReexports replaces export * edges pointing at barrels with one
+// star edge per module the barrel exposes, recording the barrel in Via
+func expandStarReexports(project *Project) {
+	for _, id := range sortedNodeIDs(*project) {
+		node := project.NodesMap[id]
+		edges := []ImportEdge{}
+		changed := false
+
+		for _, edge := range node.ImportEdges {
+			target, exists := project.NodesMap[edge.Target]
+			if edge.Kind != "reexport" || !edge.Star || !exists || !isBarrel(target) {
+				edges = append(edges, edge)
+				continue
+			}
+
+			seen := make(map[string]bool)
+			for _, member := range starMembers(project, edge.Target, map[string]bool{id: true}) {
+				if member == id || seen[member] {
+					continue
+				}
+				seen[member] = true
+				edges = append(edges, ImportEdge{Target: member, Kind: "reexport", Star: true, Via: edge.Target, Line: edge.Line})
+			}
+			changed = true
+		}
+
+		if changed {
+			node.ImportEdges = edges
+			node.Imports = edgeTargets(edges)
+			project.NodesMap[id] = node
+		}
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenBarrels rewrites imports of named symbols from barrel files into direct
+// edges to the files that define them, recording the barrel and any renames.
+// Star re-exports of barrels are first fanned out to the modules they expose.
+// Every binding is resolved against the graph as scanned before any edge is
+// rewritten, so chains of barrels flatten the same way in any order.
+func flattenBarrels(project *Project) {
+	expandStarReexports(project)
+
+	type binding struct{ id, symbol string }
+	type definition struct {
+		target, original string
+		ok               bool
+	}
+	definitions := make(map[binding]definition)
+	resolve := func(id, symbol string) (string, string, bool) {
+		key := binding{id, symbol}
+		if def, seen := definitions[key]; seen {
+			return def.target, def.original, def.ok
+		}
+		target, original, ok := resolveReexport(project, id, symbol, make(map[string]bool))
+		definitions[key] = definition{target, original, ok}
+		return target, original, ok
+	}
+
+	rewritten := make(map[string][]ImportEdge)
+	for _, id := range sortedNodeIDs(*project) {
+		edges := []ImportEdge{}
+		changed := false
+
+		for _, edge := range project.NodesMap[id].ImportEdges {
+			if edge.Kind != "import" || (len(edge.Named) == 0 && edge.Default == "") {
+				edges = append(edges, edge)
+				continue
+			}
+
+			// A default import from a pass-through file links to the real default
+			if edge.Default != "" {
+				if target, original, ok := resolve(edge.Target, "default"); ok {
+					flattened := ImportEdge{Target: target, Kind: "import", Via: edge.Target, Line: edge.Line}
+					if original == "default" {
+						flattened.Default = edge.Default
+					} else {
+						flattened.Named = []string{original}
+						flattened.LocalNames = map[string]string{original: edge.Default}
+					}
+					flattened.Reexports = movedReexports(edge.Reexports, "default", original)
+					edges = append(edges, flattened)
+					edge.Default = ""
+					edge.Reexports = keptReexports(edge.Reexports, "default")
+					changed = true
+				}
+			}
+
+			remaining := []string{}
+			for _, symbol := range edge.Named {
+				target, original, ok := resolve(edge.Target, symbol)
+				if !ok {
+					remaining = append(remaining, symbol)
+					continue
+				}
+
+				flattened := ImportEdge{Target: target, Kind: "import", Via: edge.Target, Line: edge.Line}
+				if local := edge.localName(symbol); original == "default" {
+					flattened.Default = local
+				} else {
+					flattened.Named = []string{original}
+					if local != original {
+						flattened.LocalNames = map[string]string{original: local}
+					}
+				}
+				if original != symbol {
+					flattened.Renamed = map[string]string{original: symbol}
+				}
+				flattened.Reexports = movedReexports(edge.Reexports, symbol, original)
+				edges = append(edges, flattened)
+				edge.Reexports = keptReexports(edge.Reexports, symbol)
+				changed = true
+			}
+
+			// Keep the barrel edge only for bindings it still provides
+			edge.Named = remaining
+			edge.LocalNames = keepLocalNames(edge.LocalNames, remaining)
+			if len(remaining) > 0 || edge.Default != "" || edge.Namespace != "" {
+				edges = append(edges, edge)
+			}
+		}
+
+		if changed {
+			rewritten[id] = edges
+		}
+	}
+
+	for id, edges := range rewritten {
+		node := project.NodesMap[id]
+		node.ImportEdges = edges
+		node.Imports = edgeTargets(edges)
+		project.NodesMap[id] = node
+	}
+}
+
+// movedReexports returns the pass-through exports of the imported name
+// symbol, renamed to original for an edge pointing at the defining file
+func movedReexports(reexports []ExportAlias, symbol, original string) []ExportAlias {
+	var moved []ExportAlias
+	for _, alias := range reexports {
+		if alias.Local == symbol {
+			moved = append(moved, ExportAlias{Local: original, Exported: alias.Exported})
+		}
+	}
+	return moved
+}
+
+// keptReexports returns the pass-through exports of every imported name
+// except symbol
+func keptReexports(reexports []ExportAlias, symbol string) []ExportAlias {
+	var kept []ExportAlias
+	for _, alias := range reexports {
+		if alias.Local != symbol {
+			kept = append(kept, alias)
+		}
+	}
+	return kept
+}
+
+// keepLocalNames returns the local name mappings of the given imported names
+func keepLocalNames(localNames map[string]string, names []string) map[string]string {
+	var kept map[string]string
+	for _, name := range names {
+		if local, ok := localNames[name]; ok {
+			if kept == nil {
+				kept = make(map[string]string)
+			}
+			kept[name] = local
+		}
+	}
+	return kept
+}
+
+// BarrelChain is the longest path of re-exports leading to a source file,
+// outermost barrel first and the source file last
+type BarrelChain struct {
+	Leaf   string   `json:"leaf"`
+	Chain  []string `json:"chain"`
+	Length int      `json:"length"` // number of re-export hops
+}
+
+// findBarrelChains returns the longest re-export chain ending at every file
+// that barrels re-export but that is not a barrel itself, sorted by leaf.
+// Re-export cycles are cut where they close.
+func findBarrelChains(project Project) []BarrelChain {
+	reexporters := make(map[string][]string)
+	for _, id := range sortedNodeIDs(project) {
+		for _, edge := range project.NodesMap[id].ImportEdges {
+			if _, exists := project.NodesMap[edge.Target]; exists && edge.Kind == "reexport" &&
+				!containsString(reexporters[edge.Target], id) {
+				reexporters[edge.Target] = append(reexporters[edge.Target], id)
+			}
+		}
+	}
+
+	// longest returns the longest chain of barrels ending at id, outermost first
+	longest := make(map[string][]string)
+	onPath := make(map[string]bool)
+	var chainTo func(id string) []string
+	chainTo = func(id string) []string {
+		if chain, ok := longest[id]; ok {
+			return chain
+		}
+		onPath[id] = true
+		best := []string{}
+		for _, barrel := range reexporters[id] {
+			if onPath[barrel] {
+				continue
+			}
+			if chain := chainTo(barrel); len(chain)+1 > len(best) {
+				best = append(append([]string{}, chain...), barrel)
+			}
+		}
+		onPath[id] = false
+		longest[id] = best
+		return best
+	}
+
+	chains := []BarrelChain{}
+	for _, id := range sortedNodeIDs(project) {
+		if len(reexporters[id]) == 0 || isBarrel(project.NodesMap[id]) {
+			continue
+		}
+		barrels := chainTo(id)
+		chains = append(chains, BarrelChain{Leaf: id, Chain: append(append([]string{}, barrels...), id), Length: len(barrels)})
+	}
+
+	if len(chains) == 0 {
+		return nil
+	}
+	return chains
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRenamedReexport(t *testing.T) {
+	files := map[string]string{
+		"src/components/index.ts":   "export { Button as PrimaryButton } from './Button'\n",
+		"src/components/Button.tsx": "export function Button() { return <button /> }\n",
+		"src/App.tsx":               "import { PrimaryButton } from './components'\nexport const App = () => <PrimaryButton />\n",
+	}
+
+	project := scanFiles(t, files, ScanOptions{})
+	reexport, ok := edgeTo(t, project, "src/components/index.ts", "src/components/Button.tsx")
+	if !ok {
+		t.Fatal("no re-export edge from the barrel to Button.tsx")
+	}
+	if want := []ExportAlias{{Local: "Button", Exported: "PrimaryButton"}}; !reflect.DeepEqual(reexport.Reexports, want) {
+		t.Errorf("re-exports = %+v, want %+v", reexport.Reexports, want)
+	}
+
+	project = scanFiles(t, files, ScanOptions{FlattenBarrels: true})
+	edge, ok := edgeTo(t, project, "src/App.tsx", "src/components/Button.tsx")
+	if !ok {
+		t.Fatalf("App.tsx imports %v, want src/components/Button.tsx", nodeAt(t, project, "src/App.tsx").Imports)
+	}
+	if !reflect.DeepEqual(edge.Named, []string{"Button"}) ||
+		!reflect.DeepEqual(edge.Renamed, map[string]string{"Button": "PrimaryButton"}) ||
+		edge.Via != filepath.Join("src", "components", "index.ts") {
+		t.Errorf("flattened edge = %+v", edge)
+	}
+}
+
+func TestNestedStarReexports(t *testing.T) {
+	files := map[string]string{
+		"src/index.ts":                   "export * from './components'\nexport * from './hooks'\n",
+		"src/components/index.ts":        "export * from './forms'\nexport * from './Button'\n",
+		"src/components/Button.tsx":      "export function Button() { return <button /> }\n",
+		"src/components/forms/index.ts":  "export * from './Input'\nexport * from '../index'\n",
+		"src/components/forms/Input.tsx": "export function Input() { return <input /> }\n",
+		"src/hooks/index.ts":             "export * from './useToggle'\n",
+		"src/hooks/useToggle.ts":         "export function useToggle() { return [] }\n",
+	}
+
+	project := scanFiles(t, files, ScanOptions{FlattenBarrels: true})
+	root := nodeAt(t, project, "src/index.ts")
+	want := []string{"src/components/Button.tsx", "src/components/forms/Input.tsx", "src/hooks/useToggle.ts"}
+	got := []string{}
+	for _, edge := range root.ImportEdges {
+		if !edge.Star || edge.Kind != "reexport" {
+			t.Errorf("edge = %+v, want a star re-export", edge)
+		}
+		got = append(got, filepath.ToSlash(edge.Target))
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("root barrel links %v, want %v", got, want)
+	}
+
+	// Without flattening the barrel keeps one edge per star re-export
+	project = scanFiles(t, files, ScanOptions{})
+	if imports := nodeAt(t, project, "src/index.ts").Imports; len(imports) != 2 {
+		t.Errorf("unflattened imports = %v, want the two barrels", imports)
+	}
+}
+
+func TestChainedBarrels(t *testing.T) {
+	files := map[string]string{
+		"src/App.tsx":              "import { Button } from './ui'\nexport const App = () => <Button />\n",
+		"src/ui/index.ts":          "export { Button } from './buttons'\n",
+		"src/ui/buttons/index.ts":  "export { Button } from './Themed'\n",
+		"src/ui/buttons/Themed.ts": "import { Button } from '../base'\nexport { Button }\n",
+		"src/ui/base/index.ts":     "export { Button } from './Button'\n",
+		"src/ui/base/Button.tsx":   "export function Button() { return <button /> }\n",
+	}
+
+	// Map iteration order varies between runs, so flatten a few times
+	for run := 0; run < 10; run++ {
+		project := scanFiles(t, files, ScanOptions{FlattenBarrels: true})
+
+		edge, ok := edgeTo(t, project, "src/App.tsx", "src/ui/base/Button.tsx")
+		if !ok {
+			t.Fatalf("run %d: App.tsx imports %v, want src/ui/base/Button.tsx", run, nodeAt(t, project, "src/App.tsx").Imports)
+		}
+		if !reflect.DeepEqual(edge.Named, []string{"Button"}) || edge.Via != filepath.Join("src", "ui", "index.ts") {
+			t.Errorf("run %d: App.tsx edge = %+v", run, edge)
+		}
+
+		// The pass-through file keeps re-exporting what it now imports directly
+		edge, ok = edgeTo(t, project, "src/ui/buttons/Themed.ts", "src/ui/base/Button.tsx")
+		if !ok {
+			t.Fatalf("run %d: Themed.ts imports %v", run, nodeAt(t, project, "src/ui/buttons/Themed.ts").Imports)
+		}
+		if want := []ExportAlias{{Local: "Button", Exported: "Button"}}; !reflect.DeepEqual(edge.Reexports, want) {
+			t.Errorf("run %d: Themed.ts re-exports = %+v, want %+v", run, edge.Reexports, want)
+		}
+	}
+}
+
+func TestDefaultReexports(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/App.tsx":           "import Card from './card'\nimport Button from './button'\nimport { Badge } from './badge'\n",
+		"src/card/index.ts":     "export { default } from './Card'\n",
+		"src/card/Card.tsx":     "export default function Card() { return <div /> }\n",
+		"src/button/index.ts":   "import Button from './Button'\nexport default Button\n",
+		"src/button/Button.tsx": "export default function Button() { return <button /> }\n",
+		"src/badge/index.ts":    "export { default as Badge } from './Badge'\n",
+		"src/badge/Badge.tsx":   "export default function Badge() { return <span /> }\n",
+	}, ScanOptions{FlattenBarrels: true})
+
+	tests := []struct {
+		target, via, local string
+	}{
+		{"src/card/Card.tsx", "src/card/index.ts", "Card"},
+		{"src/button/Button.tsx", "src/button/index.ts", "Button"},
+		{"src/badge/Badge.tsx", "src/badge/index.ts", "Badge"},
+	}
+	for _, tt := range tests {
+		edge, ok := edgeTo(t, project, "src/App.tsx", tt.target)
+		if !ok {
+			t.Errorf("App.tsx imports %v, want %s", nodeAt(t, project, "src/App.tsx").Imports, tt.target)
+			continue
+		}
+		if edge.Default != tt.local || edge.Via != filepath.FromSlash(tt.via) {
+			t.Errorf("edge to %s = %+v, want default %s via %s", tt.target, edge, tt.local, tt.via)
+		}
+	}
+	if imports := nodeAt(t, project, "src/App.tsx").Imports; len(imports) != 3 {
+		t.Errorf("App.tsx imports %v, want only the defining files", imports)
+	}
+}
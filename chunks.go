@@ -0,0 +1,98 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Chunk is a code-split chunk: the nodes only reachable through a lazy import
+type Chunk struct {
+	Entry   string   `json:"entry"`
+	Members []string `json:"members"`
+}
+
+// lazyWrapperRegex matches the text preceding an import() call wrapped in a
+// lazy loader, e.g. "React.lazy(() => ", "loadable(async () => { return " or
+// the React Router data API's "lazy: () => "
+var lazyWrapperRegex = regexp.MustCompile(`\b(?:(?:lazy|loadable)\(|lazy\s*:)\s*(?:async\s*)?\(\s*\)\s*=>\s*(?:\{\s*return\s+)?$`)
+
+// isLazyImport reports whether the import() call at offset is wrapped in a lazy loader
+func isLazyImport(content string, offset int) bool {
+	start := max(0, offset-80)
+	return lazyWrapperRegex.MatchString(content[start:offset])
+}
+
+// isSplitEdge reports whether an edge crosses a code-split boundary
+func isSplitEdge(edge ImportEdge) bool {
+	return edge.Kind == "lazy" || edge.Kind == "dynamic"
+}
+
+// staticReachable returns the nodes reachable from starts without crossing
+// code-split boundaries
+func staticReachable(project Project, starts []string) map[string]bool {
+	reachable := make(map[string]bool)
+	queue := []string{}
+
+	for _, start := range starts {
+		if _, exists := project.NodesMap[start]; exists && !reachable[start] {
+			reachable[start] = true
+			queue = append(queue, start)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range project.NodesMap[id].ImportEdges {
+			if isSplitEdge(edge) {
+				continue
+			}
+			if _, exists := project.NodesMap[edge.Target]; exists && !reachable[edge.Target] {
+				reachable[edge.Target] = true
+				queue = append(queue, edge.Target)
+			}
+		}
+	}
+
+	return reachable
+}
+
+// computeChunks groups nodes into lazy-loaded chunks. Each lazy import target
+// starts a chunk containing everything it statically reaches that the main
+// entries do not.
+func computeChunks(project Project, entries []string) []Chunk {
+	main := staticReachable(project, entries)
+
+	lazyTargets := make(map[string]bool)
+	for _, node := range project.NodesMap {
+		for _, edge := range node.ImportEdges {
+			if edge.Kind == "lazy" {
+				if _, exists := project.NodesMap[edge.Target]; exists {
+					lazyTargets[edge.Target] = true
+				}
+			}
+		}
+	}
+
+	chunks := []Chunk{}
+	for target := range lazyTargets {
+		if main[target] {
+			continue // already shipped in the main bundle
+		}
+
+		members := []string{}
+		for id := range staticReachable(project, []string{target}) {
+			if !main[id] {
+				members = append(members, id)
+			}
+		}
+		sort.Strings(members)
+		chunks = append(chunks, Chunk{Entry: target, Members: members})
+	}
+
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Entry < chunks[j].Entry
+	})
+	return chunks
+}
@@ -0,0 +1,155 @@
+package main
+
+import "path/filepath"
+
+// CollapseSubtree returns a copy of the project in which every node under the
+// directory dirOrNodeID (or that node alone, for a file) is folded into one
+// "collapsed" node with the directory's ID. Edges between the folded nodes
+// disappear; edges to or from the rest of the graph are rerouted onto the
+// collapsed node, merging duplicates and recording how many edges each merged
+// edge stands for in ImportEdge.Count. The project passed in is left
+// untouched; collapsing a path with no nodes returns it unchanged.
+func CollapseSubtree(project Project, dirOrNodeID string) Project {
+	target := filepath.Clean(filepath.FromSlash(dirOrNodeID))
+	members := make(map[string]bool)
+	for _, id := range sortedNodeIDs(project) {
+		if pathWithin(id, target) {
+			members[id] = true
+		}
+	}
+	if len(members) == 0 || target == "." {
+		return project
+	}
+
+	collapsed := project
+	collapsed.NodesMap = make(map[string]ComponentNode, len(project.NodesMap))
+	aggregate := ComponentNode{
+		ID:         target,
+		Name:       filepath.Base(target),
+		Path:       target,
+		Type:       "collapsed",
+		Imports:    []string{},
+		ImportedBy: []string{},
+	}
+	folded := []string{}
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		if !members[id] {
+			collapsed.NodesMap[id] = node
+			continue
+		}
+		folded = append(folded, id)
+		aggregate.Imports = append(aggregate.Imports, node.Imports...)
+		aggregate.ImportedBy = append(aggregate.ImportedBy, node.ImportedBy...)
+		aggregate.ImportEdges = append(aggregate.ImportEdges, node.ImportEdges...)
+		aggregate.ImportedByDetails = append(aggregate.ImportedByDetails, node.ImportedByDetails...)
+		aggregate.Externals = append(aggregate.Externals, node.Externals...)
+		aggregate.Complexity.Imports += node.Complexity.Imports
+		aggregate.Complexity.JSXTags += node.Complexity.JSXTags
+		aggregate.Complexity.Hooks += node.Complexity.Hooks
+		aggregate.Complexity.LOC += node.Complexity.LOC
+		aggregate.Complexity.Score += node.Complexity.Score
+	}
+	collapsed.NodesMap[target] = aggregate
+
+	// Point every reference to a folded node at the collapsed one
+	files := project.Files
+	projectRemapper{path: func(id string) string {
+		if members[id] {
+			return target
+		}
+		return id
+	}}.apply(&collapsed)
+	collapsed.Files = files
+	aggregate = collapsed.NodesMap[target]
+	aggregate.Collapsed = folded
+	aggregate.Externals = mergeRefs(aggregate.Externals, "", "")
+	collapsed.NodesMap[target] = aggregate
+
+	for id, node := range collapsed.NodesMap {
+		if id != target && !containsString(node.Imports, target) && !containsString(node.ImportedBy, target) {
+			continue
+		}
+		node.Imports = mergeRefs(node.Imports, id, target)
+		node.ImportedBy = mergeRefs(node.ImportedBy, id, target)
+		node.ImportEdges = mergeEdges(node.ImportEdges, id, target)
+
+		refs := []ImporterRef{}
+		seen := make(map[string]bool)
+		for _, ref := range node.ImportedByDetails {
+			if ref.Importer == id || seen[ref.Importer] {
+				continue
+			}
+			if id == target || ref.Importer == target {
+				seen[ref.Importer] = true
+			}
+			refs = append(refs, ref)
+		}
+		node.ImportedByDetails = refs
+		collapsed.NodesMap[id] = node
+	}
+	for _, list := range []*[]string{&collapsed.EntryPoints, &collapsed.Unreachable, &collapsed.Untested,
+		&collapsed.MostComplex, &collapsed.TopRanked} {
+		if *list != nil {
+			*list = mergeRefs(*list, "", target)
+		}
+	}
+
+	// Rebuild the tree, stats and derived analyses around the collapsed node
+	return subgraph(collapsed, func(ComponentNode) bool { return true })
+}
+
+// mergeRefs drops a node's references to itself and repeated references to
+// the collapsed node target. Within the collapsed node itself, whose
+// references come from several files, every repeat is dropped.
+func mergeRefs(values []string, self, target string) []string {
+	seen := make(map[string]bool)
+	merged := []string{}
+	for _, value := range values {
+		if value == self || seen[value] {
+			continue
+		}
+		if self == target || value == target {
+			seen[value] = true
+		}
+		merged = append(merged, value)
+	}
+	return merged
+}
+
+// mergeEdges drops a node's edges to itself and merges its edges of one kind
+// to the collapsed node, or all same-kind edges to one target within the
+// collapsed node, summing their counts and render counts and joining symbols
+func mergeEdges(edges []ImportEdge, self, target string) []ImportEdge {
+	merged := []ImportEdge{}
+	index := make(map[[2]string]int)
+	for _, edge := range edges {
+		if edge.Target == self {
+			continue
+		}
+		if self != target && edge.Target != target {
+			merged = append(merged, edge)
+			continue
+		}
+
+		count := max(edge.Count, 1)
+		key := [2]string{edge.Target, edge.Kind}
+		if i, exists := index[key]; exists {
+			merged[i].Count += count
+			merged[i].RenderCount += edge.RenderCount
+			merged[i].Named = mergeRefs(append(append([]string{}, merged[i].Named...), edge.Named...), "", "")
+			continue
+		}
+		edge.Count = count
+		index[key] = len(merged)
+		merged = append(merged, edge)
+	}
+
+	// An edge standing for a single import needs no count
+	for i := range merged {
+		if merged[i].Count == 1 {
+			merged[i].Count = 0
+		}
+	}
+	return merged
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Complexity is a heuristic breakdown used to rank components worth refactoring.
+// Score is a weighted sum of the other signals, not a cyclomatic metric.
+type Complexity struct {
+	Imports int `json:"imports"`
+	JSXTags int `json:"jsxTags"`
+	Hooks   int `json:"hooks"`
+	LOC     int `json:"loc"`
+	Score   int `json:"score"`
+}
+
+// jsxTagRegex matches opening JSX tags such as <div or <Foo.Bar
+var jsxTagRegex = regexp.MustCompile(`<[A-Za-z][\w.]*[\s/>]`)
+
+// hookCallRegex matches React hook calls such as useState( or useCustomThing(
+var hookCallRegex = regexp.MustCompile(`\buse[A-Z]\w*\s*\(`)
+
+// mostComplexLimit is the number of components listed in Project.MostComplex
+const mostComplexLimit = 10
+
+// measureComplexity gathers the complexity signals for a file
+func measureComplexity(content string, importCount int) Complexity {
+	c := Complexity{
+		Imports: importCount,
+		JSXTags: len(jsxTagRegex.FindAllStringIndex(content, -1)),
+		Hooks:   len(hookCallRegex.FindAllStringIndex(content, -1)),
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			c.LOC++
+		}
+	}
+
+	c.Score = c.Imports*2 + c.JSXTags + c.Hooks*3 + c.LOC/10
+	return c
+}
+
+// mostComplexComponents returns the highest-scoring component nodes
+func mostComplexComponents(project Project, limit int) []string {
+	ids := []string{}
+	for id, node := range project.NodesMap {
+		if node.Type == "component" || node.Type == "route" {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := project.NodesMap[ids[i]].Complexity.Score, project.NodesMap[ids[j]].Complexity.Score
+		if a != b {
+			return a > b
+		}
+		return ids[i] < ids[j]
+	})
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// envCondition is a region of source code that only runs when an environment check passes
+type envCondition struct {
+	Condition  string
+	Start, End int
+}
+
+// ifStatementRegex matches the start of an if statement's condition
+var ifStatementRegex = regexp.MustCompile(`\bif\s*\(`)
+
+// envShortCircuitRegex matches an environment check guarding the rest of an expression,
+// e.g. "process.env.NODE_ENV === 'development' && "
+var envShortCircuitRegex = regexp.MustCompile(`((?:process\.env|import\.meta\.env)[^;\n&|]*?|__DEV__)\s*&&\s*$`)
+
+// isEnvCheck reports whether a condition inspects the build environment
+func isEnvCheck(condition string) bool {
+	return strings.Contains(condition, "process.env") ||
+		strings.Contains(condition, "import.meta.env") ||
+		strings.Contains(condition, "__DEV__")
+}
+
+// findEnvConditions locates the bodies of if statements whose condition checks
+// the environment. This is a coarse, brace-matching heuristic rather than a parser.
+func findEnvConditions(content string) []envCondition {
+	conditions := []envCondition{}
+
+	for _, loc := range ifStatementRegex.FindAllStringIndex(content, -1) {
+		condEnd := matchingClose(content, loc[1]-1, '(', ')')
+		if condEnd < 0 {
+			continue
+		}
+
+		condition := strings.TrimSpace(content[loc[1]:condEnd])
+		if !isEnvCheck(condition) {
+			continue
+		}
+
+		// The body is either a braced block or a single statement
+		bodyStart := condEnd + 1
+		for bodyStart < len(content) && (content[bodyStart] == ' ' || content[bodyStart] == '\t' ||
+			content[bodyStart] == '\n' || content[bodyStart] == '\r') {
+			bodyStart++
+		}
+
+		bodyEnd := -1
+		if bodyStart < len(content) && content[bodyStart] == '{' {
+			bodyEnd = matchingClose(content, bodyStart, '{', '}')
+		} else if idx := strings.IndexAny(content[bodyStart:], ";\n"); idx >= 0 {
+			bodyEnd = bodyStart + idx
+		}
+		if bodyEnd < 0 {
+			bodyEnd = len(content)
+		}
+
+		conditions = append(conditions, envCondition{Condition: condition, Start: bodyStart, End: bodyEnd})
+	}
+
+	return conditions
+}
+
+// conditionAt returns the environment condition guarding the given offset, if any.
+// Besides if blocks it recognises short-circuit guards on the same line.
+func conditionAt(conditions []envCondition, content string, offset int) (string, bool) {
+	for _, condition := range conditions {
+		if offset >= condition.Start && offset < condition.End {
+			return condition.Condition, true
+		}
+	}
+
+	lineStart := strings.LastIndex(content[:offset], "\n") + 1
+	if match := envShortCircuitRegex.FindStringSubmatch(content[lineStart:offset]); match != nil {
+		return strings.TrimSpace(match[1]), true
+	}
+
+	return "", false
+}
+
+// matchingClose returns the index of the bracket closing the one at openIdx, or -1
+func matchingClose(content string, openIdx int, open, close byte) int {
+	depth := 0
+	for i := openIdx; i < len(content); i++ {
+		switch content[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestConditionalImports(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/index.js": `import App from './App'
+
+if (process.env.NODE_ENV === 'development') {
+  require('./devtools')
+}
+
+const loadAnalytics = () => import('./analytics')
+`,
+		"src/App.jsx":      "export default function App() { return <div /> }\n",
+		"src/devtools.js":  "export const install = () => {}\n",
+		"src/analytics.js": "export const track = () => {}\n",
+	}, ScanOptions{})
+
+	devtools, ok := edgeTo(t, project, "src/index.js", "src/devtools.js")
+	if !ok {
+		t.Fatal("no edge to devtools.js")
+	}
+	if !devtools.Conditional || devtools.Condition != "process.env.NODE_ENV === 'development'" {
+		t.Errorf("devtools edge conditional = %v, condition = %q", devtools.Conditional, devtools.Condition)
+	}
+
+	for _, target := range []string{"src/App.jsx", "src/analytics.js"} {
+		edge, ok := edgeTo(t, project, "src/index.js", target)
+		if !ok {
+			t.Fatalf("no edge to %s", target)
+		}
+		if edge.Conditional {
+			t.Errorf("edge to %s is marked conditional", target)
+		}
+	}
+}
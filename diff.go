@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Edge is a directed import relationship between two nodes
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// ProjectDiff describes the structural changes between two scans
+type ProjectDiff struct {
+	AddedNodes   []string `json:"addedNodes"`
+	RemovedNodes []string `json:"removedNodes"`
+	ChangedNodes []string `json:"changedNodes"` // nodes whose type changed
+	AddedEdges   []Edge   `json:"addedEdges"`
+	RemovedEdges []Edge   `json:"removedEdges"`
+}
+
+// ProjectPatch is the compact wire form of a ProjectDiff, with short keys
+// and edges encoded as [source, target] pairs
+type ProjectPatch struct {
+	AddedNodes   []string    `json:"+n,omitempty"`
+	RemovedNodes []string    `json:"-n,omitempty"`
+	ChangedNodes []string    `json:"~n,omitempty"`
+	AddedEdges   [][2]string `json:"+e,omitempty"`
+	RemovedEdges [][2]string `json:"-e,omitempty"`
+}
+
+// Empty reports whether the diff contains no changes
+func (d ProjectDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ChangedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Patch serializes the diff as a compact JSON patch
+func (d ProjectDiff) Patch() ([]byte, error) {
+	pairs := func(edges []Edge) [][2]string {
+		encoded := make([][2]string, len(edges))
+		for i, edge := range edges {
+			encoded[i] = [2]string{edge.Source, edge.Target}
+		}
+		return encoded
+	}
+
+	return json.Marshal(ProjectPatch{
+		AddedNodes:   d.AddedNodes,
+		RemovedNodes: d.RemovedNodes,
+		ChangedNodes: d.ChangedNodes,
+		AddedEdges:   pairs(d.AddedEdges),
+		RemovedEdges: pairs(d.RemovedEdges),
+	})
+}
+
+// DiffProjects compares two scanned projects by node ID and import edges
+func DiffProjects(oldProject, newProject Project) ProjectDiff {
+	diff := ProjectDiff{
+		AddedNodes:   []string{},
+		RemovedNodes: []string{},
+		ChangedNodes: []string{},
+		AddedEdges:   []Edge{},
+		RemovedEdges: []Edge{},
+	}
+
+	for id, node := range newProject.NodesMap {
+		oldNode, exists := oldProject.NodesMap[id]
+		if !exists {
+			diff.AddedNodes = append(diff.AddedNodes, id)
+		} else if oldNode.Type != node.Type {
+			diff.ChangedNodes = append(diff.ChangedNodes, id)
+		}
+	}
+
+	for id := range oldProject.NodesMap {
+		if _, exists := newProject.NodesMap[id]; !exists {
+			diff.RemovedNodes = append(diff.RemovedNodes, id)
+		}
+	}
+
+	oldEdges := projectEdges(oldProject)
+	newEdges := projectEdges(newProject)
+
+	for edge := range newEdges {
+		if !oldEdges[edge] {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+
+	for edge := range oldEdges {
+		if !newEdges[edge] {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	sort.Strings(diff.ChangedNodes)
+	sortEdges(diff.AddedEdges)
+	sortEdges(diff.RemovedEdges)
+
+	return diff
+}
+
+// AnnotatedDiff returns the union of two scans, with every node and edge
+// carrying a changeStatus of added, removed, changed (node type only) or
+// unchanged so both graphs can be rendered overlaid
+func AnnotatedDiff(oldProject, newProject Project) Project {
+	union := newProject
+	union.Root.Children = nil
+	union.NodesMap = make(map[string]ComponentNode, len(newProject.NodesMap))
+	union.Files = append([]string{}, newProject.Files...)
+	union.Stats = ProjectStats{}
+
+	targets := func(node ComponentNode) map[string]bool {
+		set := make(map[string]bool)
+		for _, target := range node.Imports {
+			set[target] = true
+		}
+		return set
+	}
+
+	for id, node := range newProject.NodesMap {
+		oldNode, existed := oldProject.NodesMap[id]
+		switch {
+		case !existed:
+			node.ChangeStatus = "added"
+		case oldNode.Type != node.Type:
+			node.ChangeStatus = "changed"
+		default:
+			node.ChangeStatus = "unchanged"
+		}
+
+		oldTargets := targets(oldNode)
+		newTargets := targets(node)
+		edges := []ImportEdge{}
+		for _, edge := range node.ImportEdges {
+			edge.ChangeStatus = "unchanged"
+			if !oldTargets[edge.Target] {
+				edge.ChangeStatus = "added"
+			}
+			edges = append(edges, edge)
+		}
+		for _, edge := range oldNode.ImportEdges {
+			if !newTargets[edge.Target] {
+				edge.ChangeStatus = "removed"
+				edges = append(edges, edge)
+			}
+		}
+		node.ImportEdges = edges
+		union.NodesMap[id] = node
+	}
+
+	for id, node := range oldProject.NodesMap {
+		if _, exists := newProject.NodesMap[id]; exists {
+			continue
+		}
+		node.ChangeStatus = "removed"
+		edges := make([]ImportEdge, len(node.ImportEdges))
+		for i, edge := range node.ImportEdges {
+			edge.ChangeStatus = "removed"
+			edges[i] = edge
+		}
+		node.ImportEdges = edges
+		union.NodesMap[id] = node
+		union.Files = append(union.Files, id)
+	}
+
+	// Rebuild the reverse relationships and stats over the union
+	for id, node := range union.NodesMap {
+		node.Imports = edgeTargets(node.ImportEdges)
+		node.ImportedBy = nil
+		node.ImportedByDetails = nil
+		node.Children = nil
+		union.NodesMap[id] = node
+	}
+	buildRelationships(&union)
+	for _, node := range union.NodesMap {
+		addNodeStats(&union.Stats, node)
+	}
+	markDevEdges(&union)
+	union.Stats.ExternalPackages = countExternalPackages(union.NodesMap)
+	sort.Strings(union.Files)
+	buildTree(&union)
+
+	return union
+}
+
+// ScanAndDiff scans two project directories and returns the changes from dirA to dirB
+func ScanAndDiff(dirA, dirB string) (ProjectDiff, error) {
+	oldProject, err := ScanProject(dirA)
+	if err != nil {
+		return ProjectDiff{}, err
+	}
+
+	newProject, err := ScanProject(dirB)
+	if err != nil {
+		return ProjectDiff{}, err
+	}
+
+	ConvertProjectPathsToUnix(&oldProject)
+	ConvertProjectPathsToUnix(&newProject)
+
+	return DiffProjects(oldProject, newProject), nil
+}
+
+// projectEdges returns the set of import edges between scanned nodes
+func projectEdges(project Project) map[Edge]bool {
+	edges := make(map[Edge]bool)
+	for id, node := range project.NodesMap {
+		for _, importPath := range node.Imports {
+			if _, exists := project.NodesMap[importPath]; exists {
+				edges[Edge{Source: id, Target: importPath}] = true
+			}
+		}
+	}
+	return edges
+}
+
+// sortEdges orders edges by source and then target
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ResolutionWarning reports same-named files in different packages that are
+// each imported somewhere, which in a monorepo usually means a shared file was
+// copied or is reached through inconsistent import paths. Several specifiers
+// resolving to one file are fine and not reported.
+type ResolutionWarning struct {
+	Name      string              `json:"name"`      // file name without extension, or the directory of an index file
+	Copies    []string            `json:"copies"`    // the same-named files, sorted
+	Packages  map[string]string   `json:"packages"`  // copy -> directory of its package.json, "" for the root
+	Importers map[string][]string `json:"importers"` // copy -> files importing it
+}
+
+// findResolutionWarnings groups scanned files by name and reports the groups
+// whose imported members belong to more than one package and include copies
+// with the same exports in two packages. Tests, files nothing imports and
+// same-named files with different exports, such as unrelated types.ts or
+// index.ts files, are ignored.
+func findResolutionWarnings(project Project, src sourceFS, rootDir string) []ResolutionWarning {
+	packageDirs := make(map[string]string)
+	// packageOf returns the directory of the package.json nearest to a file,
+	// looking no higher than the scanned root
+	var packageOf func(dir string) string
+	packageOf = func(dir string) string {
+		if pkg, ok := packageDirs[dir]; ok {
+			return pkg
+		}
+		pkg := ""
+		if _, err := src.Stat(filepath.Join(rootDir, dir, "package.json")); err == nil {
+			pkg = dir
+		} else if dir != "." && dir != "" {
+			pkg = packageOf(filepath.Dir(dir))
+		}
+		packageDirs[dir] = pkg
+		return pkg
+	}
+
+	groups := make(map[string][]string)
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		if node.Type == "test" || node.Type == "external" || len(node.ImportedBy) == 0 {
+			continue
+		}
+		groups[duplicateName(id)] = append(groups[duplicateName(id)], id)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	warnings := []ResolutionWarning{}
+	for _, name := range names {
+		copies := groups[name]
+		if len(copies) < 2 {
+			continue
+		}
+
+		warning := ResolutionWarning{Name: name, Copies: copies, Packages: make(map[string]string), Importers: make(map[string][]string)}
+		packages := make(map[string]bool)
+		signatures := make(map[string]map[string]bool) // export names -> packages
+		copied := false
+		for _, id := range copies {
+			pkg := packageOf(filepath.Dir(id))
+			if pkg == "." {
+				pkg = ""
+			}
+			packages[pkg] = true
+			warning.Packages[id] = pkg
+
+			if signature := strings.Join(project.NodesMap[id].Exports, ","); signature != "" {
+				if signatures[signature] == nil {
+					signatures[signature] = make(map[string]bool)
+				}
+				signatures[signature][pkg] = true
+				copied = copied || len(signatures[signature]) > 1
+			}
+
+			importers := append([]string{}, project.NodesMap[id].ImportedBy...)
+			sort.Strings(importers)
+			warning.Importers[id] = importers
+		}
+		if len(packages) > 1 && copied {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+	return warnings
+}
+
+// duplicateName is the name files are compared by: the base name without
+// extension, or the parent directory for index files
+func duplicateName(id string) string {
+	base := filepath.Base(id)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if name == "index" {
+		return filepath.Base(filepath.Dir(id))
+	}
+	return name
+}
+
+// keepResolutionWarnings restricts warnings to the kept nodes, dropping those
+// left without imported copies in two packages
+func keepResolutionWarnings(warnings []ResolutionWarning, kept map[string]bool) []ResolutionWarning {
+	var filtered []ResolutionWarning
+	for _, warning := range warnings {
+		sub := ResolutionWarning{Name: warning.Name, Packages: make(map[string]string), Importers: make(map[string][]string)}
+		packages := make(map[string]bool)
+		for _, id := range warning.Copies {
+			importers := []string{}
+			for _, importer := range warning.Importers[id] {
+				if kept[importer] {
+					importers = append(importers, importer)
+				}
+			}
+			if !kept[id] || len(importers) == 0 {
+				continue
+			}
+			sub.Copies = append(sub.Copies, id)
+			sub.Packages[id] = warning.Packages[id]
+			sub.Importers[id] = importers
+			packages[warning.Packages[id]] = true
+		}
+		if len(packages) > 1 {
+			filtered = append(filtered, sub)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindResolutionWarnings(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"packages/web/package.json":        `{"name": "web"}`,
+		"packages/web/src/App.tsx":         "import { formatPrice } from './utils/format'\nimport { Theme } from './types'\nimport { api } from './api'\n",
+		"packages/web/src/utils/format.ts": "export const formatPrice = (n) => n.toFixed(2)\n",
+		"packages/web/src/types.ts":        "export type Theme = 'light' | 'dark'\n",
+		"packages/web/src/api/index.ts":    "export const api = {}\n",
+		"packages/admin/package.json":      `{"name": "admin"}`,
+		"packages/admin/src/App.tsx":       "import { formatPrice } from './format'\nimport { User } from './types'\nimport { client } from './api'\n",
+		"packages/admin/src/format.ts":     "export const formatPrice = (n) => n.toFixed(2)\n",
+		"packages/admin/src/types.ts":      "export interface User { name: string }\n",
+		"packages/admin/src/api/index.ts":  "export const client = {}\n",
+	}, ScanOptions{})
+
+	// Only format is a copy; types and the api indexes just share a name
+	if len(project.ResolutionWarnings) != 1 {
+		t.Fatalf("warnings = %+v, want one for format", project.ResolutionWarnings)
+	}
+	warning := project.ResolutionWarnings[0]
+	copies := []string{filepath.Join("packages", "admin", "src", "format.ts"), filepath.Join("packages", "web", "src", "utils", "format.ts")}
+	if warning.Name != "format" || !reflect.DeepEqual(warning.Copies, copies) {
+		t.Errorf("warning = %+v, want format copied in %v", warning, copies)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// htmlScriptRegex matches the src attribute of a script tag
+var htmlScriptRegex = regexp.MustCompile(`<script\b[^>]*\bsrc\s*=\s*["']([^"']+)["']`)
+
+// DetectEntryPoints returns the project's likely entry files: src/index and
+// src/main, Next.js routes, scripts referenced by index.html or
+// public/index.html, and the main, module and browser fields of package.json.
+// Only files present in the project are returned, sorted.
+func DetectEntryPoints(project Project, rootDir string) []string {
+	return detectEntryPoints(project, sourceFS{}, rootDir)
+}
+
+// detectEntryPoints detects entry points, reading project files from src
+func detectEntryPoints(project Project, src sourceFS, rootDir string) []string {
+	entries := make(map[string]bool)
+	for _, id := range defaultEntryPoints(project) {
+		entries[id] = true
+	}
+	for _, id := range htmlEntryScripts(project, src, rootDir) {
+		entries[id] = true
+	}
+	for _, id := range packageEntryPoints(project, src, rootDir) {
+		entries[id] = true
+	}
+
+	detected := make([]string, 0, len(entries))
+	for id := range entries {
+		detected = append(detected, id)
+	}
+	sort.Strings(detected)
+	return detected
+}
+
+// htmlEntryScripts returns the nodes loaded by script tags in the root
+// index.html, as in Vite, or in public/index.html, as in Create React App.
+// Sources may be root-relative ("/src/main.tsx"), relative to the page, or
+// start with CRA's %PUBLIC_URL% placeholder for files in public.
+func htmlEntryScripts(project Project, src sourceFS, rootDir string) []string {
+	entries := []string{}
+	for _, page := range []string{"index.html", filepath.Join("public", "index.html")} {
+		content, err := src.ReadFile(filepath.Join(rootDir, page))
+		if err != nil {
+			continue
+		}
+		pageDir := filepath.Dir(page)
+
+		for _, match := range htmlScriptRegex.FindAllStringSubmatch(string(content), -1) {
+			ref := match[1]
+			candidates := []string{ref}
+			switch {
+			case strings.HasPrefix(ref, "%PUBLIC_URL%"):
+				candidates = []string{path.Join("public", strings.TrimPrefix(ref, "%PUBLIC_URL%"))}
+			case strings.HasPrefix(ref, "/"):
+				// Vite serves public at the site root, next to the sources
+				candidates = append(candidates, path.Join("public", ref))
+			default:
+				candidates = []string{path.Join(filepath.ToSlash(pageDir), ref)}
+			}
+
+			for _, candidate := range candidates {
+				if id, ok := projectFile(project, candidate); ok && !containsString(entries, id) {
+					entries = append(entries, id)
+					break
+				}
+			}
+		}
+	}
+	return entries
+}
+
+// markHTMLEntries flags the nodes loaded by script tags in index.html
+func markHTMLEntries(project *Project, src sourceFS, rootDir string) {
+	for _, id := range htmlEntryScripts(*project, src, rootDir) {
+		node := project.NodesMap[id]
+		node.HTMLEntry = true
+		project.NodesMap[id] = node
+	}
+}
+
+// packageEntryPoints returns the nodes named by the main, module and browser
+// fields of the root package.json
+func packageEntryPoints(project Project, src sourceFS, rootDir string) []string {
+	data, err := src.ReadFile(filepath.Join(rootDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var manifest struct {
+		Main    string          `json:"main"`
+		Module  string          `json:"module"`
+		Browser json.RawMessage `json:"browser"` // a path, or a map of replacements
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+
+	var browser string
+	_ = json.Unmarshal(manifest.Browser, &browser)
+
+	entries := []string{}
+	for _, field := range []string{manifest.Main, manifest.Module, browser} {
+		if id, ok := projectFile(project, field); ok {
+			entries = append(entries, id)
+		}
+	}
+	return entries
+}
+
+// projectFile maps a root-relative reference such as "/src/main.tsx" or
+// "./lib/index" to the ID of a scanned node, trying module extensions and
+// index files when the reference has none
+func projectFile(project Project, ref string) (string, bool) {
+	if ref == "" || strings.Contains(ref, "://") {
+		return "", false
+	}
+	base := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(ref, "/")))
+
+	candidates := []string{base}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, base+ext, filepath.Join(base, "index"+ext))
+	}
+	for _, candidate := range candidates {
+		if _, exists := project.NodesMap[candidate]; exists {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHTMLEntryScripts(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		entry string
+	}{
+		{"vite", map[string]string{
+			"index.html":    `<!doctype html><html><body><div id="root"></div><script type="module" src="/app/boot.tsx"></script></body></html>`,
+			"app/boot.tsx":  "import { App } from './App'\n",
+			"app/App.tsx":   "export const App = () => <div />\n",
+			"app/Other.tsx": "export const Other = () => <div />\n",
+		}, "app/boot.tsx"},
+		{"create react app", map[string]string{
+			"public/index.html":        `<html><head><script src="%PUBLIC_URL%/runtime-config.js"></script></head></html>`,
+			"public/runtime-config.js": "window.config = {}\n",
+			"app/App.tsx":              "export const App = () => <div />\n",
+		}, "public/runtime-config.js"},
+		{"relative to the page", map[string]string{
+			"index.html":   `<script type="module" src="./app/boot.jsx"></script>`,
+			"app/boot.jsx": "export {}\n",
+		}, "app/boot.jsx"},
+	}
+	for _, tt := range tests {
+		project := scanFiles(t, tt.files, ScanOptions{})
+		if !nodeAt(t, project, tt.entry).HTMLEntry {
+			t.Errorf("%s: %s is not marked as an HTML entry", tt.name, tt.entry)
+		}
+		if !reflect.DeepEqual(project.EntryPoints, []string{filepath.FromSlash(tt.entry)}) {
+			t.Errorf("%s: entry points = %v, want [%s]", tt.name, project.EntryPoints, tt.entry)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sortedNodeIDs returns the IDs of all nodes in a stable order
+func sortedNodeIDs(project Project) []string {
+	ids := make([]string, 0, len(project.NodesMap))
+	for id := range project.NodesMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// plantUMLAliasRegex matches characters that are not allowed in a PlantUML alias
+var plantUMLAliasRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// plantUMLAlias converts a node ID into a valid PlantUML alias
+func plantUMLAlias(id string) string {
+	return "n_" + plantUMLAliasRegex.ReplaceAllString(ConvertToUnixPath(id), "_")
+}
+
+// plantUMLLabel removes characters PlantUML treats as syntax from a display name
+func plantUMLLabel(name string) string {
+	return strings.NewReplacer("[", "(", "]", ")", "\"", "'", "\n", " ").Replace(name)
+}
+
+// ToPlantUML renders the project as a PlantUML component diagram, grouping
+// components into packages by directory
+func ToPlantUML(project Project) string {
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+
+	// Group nodes by directory
+	dirs := make(map[string][]string)
+	for _, id := range sortedNodeIDs(project) {
+		dir := path.Dir(ConvertToUnixPath(project.NodesMap[id].Path))
+		dirs[dir] = append(dirs[dir], id)
+	}
+
+	dirNames := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirNames = append(dirNames, dir)
+	}
+	sort.Strings(dirNames)
+
+	for _, dir := range dirNames {
+		fmt.Fprintf(&b, "package \"%s\" {\n", plantUMLLabel(dir))
+		for _, id := range dirs[dir] {
+			node := project.NodesMap[id]
+			fmt.Fprintf(&b, "  [%s] as %s <<%s>>\n", plantUMLLabel(node.Name), plantUMLAlias(id), node.Type)
+		}
+		b.WriteString("}\n")
+	}
+
+	// Dependency arrows between scanned nodes
+	for _, id := range sortedNodeIDs(project) {
+		seen := make(map[string]bool)
+		for _, importPath := range project.NodesMap[id].Imports {
+			if _, exists := project.NodesMap[importPath]; !exists || seen[importPath] {
+				continue
+			}
+			seen[importPath] = true
+			fmt.Fprintf(&b, "%s --> %s\n", plantUMLAlias(id), plantUMLAlias(importPath))
+		}
+	}
+
+	b.WriteString("@enduml\n")
+	return b.String()
+}
+
+// cytoscapeElement is a node or edge in Cytoscape.js element JSON
+type cytoscapeElement struct {
+	Data    map[string]string `json:"data"`
+	Classes string            `json:"classes,omitempty"`
+}
+
+// ToCytoscape renders the project as Cytoscape.js JSON. Nodes carry their
+// type as a class so stylesheets can target e.g. "node.component".
+func ToCytoscape(project Project) string {
+	nodes := []cytoscapeElement{}
+	edges := []cytoscapeElement{}
+
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		nodes = append(nodes, cytoscapeElement{
+			Data:    map[string]string{"id": ConvertToUnixPath(id), "label": node.Name, "type": node.Type},
+			Classes: node.Type,
+		})
+
+		seen := make(map[string]bool)
+		for _, importPath := range node.Imports {
+			if _, exists := project.NodesMap[importPath]; !exists || seen[importPath] {
+				continue
+			}
+			seen[importPath] = true
+			edges = append(edges, cytoscapeElement{
+				Data: map[string]string{"source": ConvertToUnixPath(id), "target": ConvertToUnixPath(importPath)},
+			})
+		}
+	}
+
+	doc := map[string]map[string][]cytoscapeElement{
+		"elements": {"nodes": nodes, "edges": edges},
+	}
+	data, _ := json.MarshalIndent(doc, "", "  ")
+	return string(data)
+}
+
+// FileManifest lists what a single file imports and exports
+type FileManifest struct {
+	Path    string       `json:"path"`
+	Imports []ImportEdge `json:"imports"`
+	Exports []string     `json:"exports"`
+}
+
+// ToManifest returns a flat import/export manifest with one entry per file,
+// sorted by path
+func ToManifest(project Project) []FileManifest {
+	manifest := make([]FileManifest, 0, len(project.NodesMap))
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		entry := FileManifest{Path: node.Path, Imports: node.ImportEdges, Exports: node.Exports}
+		if entry.Imports == nil {
+			entry.Imports = []ImportEdge{}
+		}
+		if entry.Exports == nil {
+			entry.Exports = []string{}
+		}
+		manifest = append(manifest, entry)
+	}
+	return manifest
+}
+
+// exporters renders a project in each supported export format
+var exporters = map[string]func(Project) ([]byte, error){
+	"json": func(project Project) ([]byte, error) {
+		var buf bytes.Buffer
+		err := WriteProjectJSON(&buf, project)
+		return buf.Bytes(), err
+	},
+	"plantuml": func(project Project) ([]byte, error) {
+		return []byte(ToPlantUML(project)), nil
+	},
+	"cytoscape": func(project Project) ([]byte, error) {
+		return []byte(ToCytoscape(project)), nil
+	},
+	"manifest": func(project Project) ([]byte, error) {
+		return json.MarshalIndent(ToManifest(project), "", "  ")
+	},
+	"text": func(project Project) ([]byte, error) {
+		return []byte(ToTextTree(project)), nil
+	},
+}
+
+// ScanAndExport scans rootDir once and renders the project in every requested
+// format ("json", "plantuml", "cytoscape", "manifest" or "text"), keyed by format
+func ScanAndExport(rootDir string, formats []string) (map[string][]byte, error) {
+	for _, format := range formats {
+		if _, ok := exporters[format]; !ok {
+			return nil, fmt.Errorf("unknown export format %q", format)
+		}
+	}
+
+	project, err := ScanProject(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	outputs := make(map[string][]byte, len(formats))
+	for _, format := range formats {
+		if _, done := outputs[format]; done {
+			continue
+		}
+		data, err := exporters[format](project)
+		if err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", format, err)
+		}
+		outputs[format] = data
+	}
+	return outputs, nil
+}
+
+// ToTextTree renders the directory tree as an indented outline in the style
+// of the tree command, annotating each node with its type. Children are
+// sorted by ID so the output is stable.
+func ToTextTree(project Project) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", project.Root.Name, project.Root.Type)
+	writeTextTree(&b, project.Root.Children, "")
+	return b.String()
+}
+
+// writeTextTree writes one level of the outline below prefix
+func writeTextTree(b *strings.Builder, children []ComponentNode, prefix string) {
+	sorted := append([]ComponentNode{}, children...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return ConvertToUnixPath(sorted[i].ID) < ConvertToUnixPath(sorted[j].ID)
+	})
+
+	for i, child := range sorted {
+		branch, indent := "├─ ", "│  "
+		if i == len(sorted)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		fmt.Fprintf(b, "%s%s%s (%s)\n", prefix, branch, child.Name, child.Type)
+		writeTextTree(b, child.Children, prefix+indent)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+// externalsNodeID is the ID of the synthetic node standing in for every
+// third-party package when externals are collapsed
+const externalsNodeID = "externals"
+
+// collapseExternals adds the synthetic externals node and an import edge to it
+// from every file that uses an external package. It runs after every other
+// pass, so filters, reachability, path rebasing and the stats only ever see
+// the project's files, and updates the copies of the importers in the tree.
+func collapseExternals(project *Project) {
+	packages := countExternalPackages(project.NodesMap)
+	if len(packages) == 0 {
+		return
+	}
+
+	externals := ComponentNode{
+		ID:         externalsNodeID,
+		Name:       externalsNodeID,
+		Path:       externalsNodeID,
+		Type:       "external",
+		Imports:    []string{},
+		ImportedBy: []string{},
+		Packages:   packages,
+	}
+	for _, id := range sortedNodeIDs(*project) {
+		node := project.NodesMap[id]
+		if len(node.Externals) == 0 {
+			continue
+		}
+		node.Imports = append(node.Imports, externalsNodeID)
+		node.ImportEdges = append(node.ImportEdges, ImportEdge{Target: externalsNodeID, Kind: "import"})
+		project.NodesMap[id] = node
+
+		externals.ImportedBy = append(externals.ImportedBy, id)
+		externals.ImportedByDetails = append(externals.ImportedByDetails, ImporterRef{Importer: id})
+	}
+	project.NodesMap[externalsNodeID] = externals
+	syncTreeNodes(project.Root.Children, project.NodesMap)
+}
+
+// syncTreeNodes replaces the file nodes in the tree with their current
+// version in nodesMap, keeping the tree's children
+func syncTreeNodes(children []ComponentNode, nodesMap map[string]ComponentNode) {
+	for i := range children {
+		if node, ok := nodesMap[children[i].ID]; ok && children[i].Type != "directory" {
+			node.Children = children[i].Children
+			children[i] = node
+		}
+		syncTreeNodes(children[i].Children, nodesMap)
+	}
+}
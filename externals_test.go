@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollapseExternalsAfterFilters(t *testing.T) {
+	files := map[string]string{
+		"src/App.tsx":    "import React from 'react'\nimport { Button } from './Button'\nimport { Card } from './Card'\nexport default function App() { return <Button /> }\n",
+		"src/Button.tsx": "import clsx from 'clsx'\nexport const Button = () => <button className={clsx('a')} />\n",
+		"src/Card.tsx":   "import { Button } from './Button'\nimport dayjs from 'dayjs'\nexport const Card = () => <Button />\n",
+	}
+	root := writeFiles(t, files)
+
+	for name, opts := range map[string]ScanOptions{
+		"MinFanIn": {MinFanIn: 1},
+		"PathBase": {PathBase: "absolute"},
+	} {
+		plain := scanDir(t, root, opts)
+		opts.CollapseExternals = true
+		collapsed := scanDir(t, root, opts)
+
+		if !reflect.DeepEqual(collapsed.Stats, plain.Stats) {
+			t.Errorf("%s: stats = %+v, want %+v as without the externals node", name, collapsed.Stats, plain.Stats)
+		}
+		if len(collapsed.NodesMap) != len(plain.NodesMap)+1 {
+			t.Errorf("%s: %d nodes, want the %d files and the externals node", name, len(collapsed.NodesMap), len(plain.NodesMap))
+		}
+
+		// Only the files left after the filters import the node
+		want := []string{}
+		for _, id := range sortedNodeIDs(plain) {
+			if len(plain.NodesMap[id].Externals) > 0 {
+				want = append(want, id)
+			}
+		}
+		externals, ok := collapsed.NodesMap[externalsNodeID]
+		if !ok {
+			t.Fatalf("%s: no externals node", name)
+		}
+		if !reflect.DeepEqual(externals.ImportedBy, want) {
+			t.Errorf("%s: externals imported by %v, want %v", name, externals.ImportedBy, want)
+		}
+		for _, id := range want {
+			if !containsString(collapsed.NodesMap[id].Imports, externalsNodeID) {
+				t.Errorf("%s: %s imports %v, want externals", name, id, collapsed.NodesMap[id].Imports)
+			}
+		}
+	}
+
+	// Path rebasing leaves the synthetic ID alone
+	project := scanDir(t, root, ScanOptions{PathBase: "absolute", CollapseExternals: true})
+	if _, ok := project.NodesMap[filepath.Join(root, externalsNodeID)]; ok {
+		t.Error("externals node was rebased like a file")
+	}
+}
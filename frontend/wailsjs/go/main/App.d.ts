@@ -1,8 +1,28 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CollapseSubtree(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function DetectEntryPoints(arg1:string):Promise<string>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function LintProject(arg1:string):Promise<string>;
+
+export function ReduxGraph(arg1:string):Promise<string>;
+
+export function ScanAndDiff(arg1:string,arg2:string):Promise<string>;
+
+export function ScanAnonymized(arg1:string):Promise<string>;
+
+export function ScanArchive(arg1:string):Promise<string>;
+
 export function ScanProject(arg1:string):Promise<string>;
 
 export function SelectDirectory():Promise<string>;
+
+export function SelectTwoDirectories():Promise<Array<string>>;
+
+export function UsageRanking(arg1:string):Promise<string>;
+
+export function ValidateConfig(arg1:string):Promise<string>;
@@ -2,10 +2,38 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CollapseSubtree(arg1, arg2) {
+  return window['go']['main']['App']['CollapseSubtree'](arg1, arg2);
+}
+
+export function DetectEntryPoints(arg1) {
+  return window['go']['main']['App']['DetectEntryPoints'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function LintProject(arg1) {
+  return window['go']['main']['App']['LintProject'](arg1);
+}
+
+export function ReduxGraph(arg1) {
+  return window['go']['main']['App']['ReduxGraph'](arg1);
+}
+
+export function ScanAndDiff(arg1, arg2) {
+  return window['go']['main']['App']['ScanAndDiff'](arg1, arg2);
+}
+
+export function ScanAnonymized(arg1) {
+  return window['go']['main']['App']['ScanAnonymized'](arg1);
+}
+
+export function ScanArchive(arg1) {
+  return window['go']['main']['App']['ScanArchive'](arg1);
+}
+
 export function ScanProject(arg1) {
   return window['go']['main']['App']['ScanProject'](arg1);
 }
@@ -13,3 +41,15 @@ export function ScanProject(arg1) {
 export function SelectDirectory() {
   return window['go']['main']['App']['SelectDirectory']();
 }
+
+export function SelectTwoDirectories() {
+  return window['go']['main']['App']['SelectTwoDirectories']();
+}
+
+export function UsageRanking(arg1) {
+  return window['go']['main']['App']['UsageRanking'](arg1);
+}
+
+export function ValidateConfig(arg1) {
+  return window['go']['main']['App']['ValidateConfig'](arg1);
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// sourceFS gives the scanner read access to project files by their OS path.
+// The zero value reads from disk; otherwise paths under root are looked up in
+// fsys, and anything outside root does not exist.
+type sourceFS struct {
+	fsys  fs.FS
+	root  string
+	stats *statCache // nil disables caching
+}
+
+// statCache remembers Stat results for the duration of a scan. Resolution
+// probes the same candidate paths over and over, and the files don't change
+// while a scan runs.
+type statCache struct {
+	mu      sync.Mutex
+	results map[string]statResult
+}
+
+// statResult is a cached Stat outcome
+type statResult struct {
+	info fs.FileInfo
+	err  error
+}
+
+// newSourceFS returns the filesystem a scan of rootDir reads from, with a
+// fresh stat cache
+func newSourceFS(rootDir string, fsys fs.FS) sourceFS {
+	return sourceFS{fsys: fsys, root: rootDir, stats: &statCache{results: make(map[string]statResult)}}
+}
+
+// name converts an OS path into a name within fsys
+func (s sourceFS) name(path string) (string, error) {
+	rel := path
+	if filepath.IsAbs(path) || strings.HasPrefix(path, s.root) {
+		var err error
+		if rel, err = filepath.Rel(s.root, path); err != nil {
+			return "", fs.ErrNotExist
+		}
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") || !fs.ValidPath(rel) {
+		return "", fs.ErrNotExist
+	}
+	return rel, nil
+}
+
+// Stat returns file info for path, from the cache when one is attached
+func (s sourceFS) Stat(path string) (fs.FileInfo, error) {
+	if s.stats == nil {
+		return s.stat(path)
+	}
+
+	s.stats.mu.Lock()
+	result, ok := s.stats.results[path]
+	s.stats.mu.Unlock()
+	if !ok {
+		result.info, result.err = s.stat(path)
+		s.stats.mu.Lock()
+		s.stats.results[path] = result
+		s.stats.mu.Unlock()
+	}
+	return result.info, result.err
+}
+
+// stat returns file info for path without caching
+func (s sourceFS) stat(path string) (fs.FileInfo, error) {
+	if s.fsys == nil {
+		return os.Stat(path)
+	}
+	name, err := s.name(path)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: err}
+	}
+	return fs.Stat(s.fsys, name)
+}
+
+// ReadFile returns the contents of path
+func (s sourceFS) ReadFile(path string) ([]byte, error) {
+	if s.fsys == nil {
+		return os.ReadFile(path)
+	}
+	name, err := s.name(path)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: err}
+	}
+	return fs.ReadFile(s.fsys, name)
+}
+
+// ReadDir returns the entries of the directory at path
+func (s sourceFS) ReadDir(path string) ([]fs.DirEntry, error) {
+	if s.fsys == nil {
+		return os.ReadDir(path)
+	}
+	name, err := s.name(path)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: path, Err: err}
+	}
+	return fs.ReadDir(s.fsys, name)
+}
+
+// Walk walks the tree under root like filepath.Walk. On disk it can follow
+// symlinked directories; an fs.FS is walked as is.
+func (s sourceFS) Walk(root string, followSymlinks bool, fn filepath.WalkFunc) error {
+	if s.fsys == nil {
+		if followSymlinks {
+			return walkFollowingSymlinks(root, fn)
+		}
+		return filepath.Walk(root, fn)
+	}
+
+	start, err := s.name(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	return fs.WalkDir(s.fsys, start, func(name string, d fs.DirEntry, err error) error {
+		path := filepath.Join(s.root, filepath.FromSlash(name))
+		if err != nil {
+			return fn(path, nil, err)
+		}
+		info, err := d.Info()
+		return fn(path, info, err)
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EnrichWithGit sets the last commit date and primary author of every node
+// from the Git history of rootDir. It runs git blame once per file, so it is
+// slow on large projects. Files outside version control are left untouched.
+func EnrichWithGit(rootDir string, project *Project) error {
+	lastModified, err := gitLastModified(rootDir)
+	if err != nil {
+		return err
+	}
+
+	for id, node := range project.NodesMap {
+		key := filepath.ToSlash(id)
+		if modified, ok := lastModified[key]; ok {
+			node.LastModified = &modified
+		}
+		if owner, err := gitOwner(rootDir, id); err == nil {
+			node.Owner = owner
+		}
+		project.NodesMap[id] = node
+	}
+	return nil
+}
+
+// gitLastModified returns the date of the most recent commit touching each
+// file, keyed by slash-separated path relative to rootDir
+func gitLastModified(rootDir string) (map[string]time.Time, error) {
+	cmd := exec.Command("git", "-C", rootDir, "log", "--relative", "--name-only", "--format=format:%x00%cI", "--", ".")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading git history of %s: %w", rootDir, err)
+	}
+
+	dates := make(map[string]time.Time)
+	var current time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\x00"):
+			current, _ = time.Parse(time.RFC3339, strings.TrimPrefix(line, "\x00"))
+		case line != "":
+			// The log is newest first, so keep the first date seen per file
+			if _, seen := dates[line]; !seen && !current.IsZero() {
+				dates[line] = current
+			}
+		}
+	}
+	return dates, scanner.Err()
+}
+
+// gitOwner returns the author of the most lines of a file according to git blame
+func gitOwner(rootDir, relPath string) (string, error) {
+	cmd := exec.Command("git", "-C", rootDir, "blame", "--line-porcelain", "--", relPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if author, ok := strings.CutPrefix(scanner.Text(), "author "); ok {
+			lines[author]++
+		}
+	}
+
+	owner := ""
+	for author, count := range lines {
+		if count > lines[owner] || (count == lines[owner] && author < owner) {
+			owner = author
+		}
+	}
+	return owner, scanner.Err()
+}
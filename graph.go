@@ -0,0 +1,271 @@
+package main
+
+// subgraph returns a copy of the project containing only the nodes accepted by
+// keep. Edges to dropped nodes are removed, and the tree and stats are rebuilt.
+func subgraph(project Project, keep func(ComponentNode) bool) Project {
+	kept := make(map[string]bool)
+	for id, node := range project.NodesMap {
+		if keep(node) {
+			kept[id] = true
+		}
+	}
+
+	keepIDs := func(ids []string) []string {
+		if ids == nil {
+			return nil
+		}
+		filtered := []string{}
+		for _, id := range ids {
+			if kept[id] {
+				filtered = append(filtered, id)
+			}
+		}
+		return filtered
+	}
+
+	sub := project
+	sub.Root.Children = nil
+	sub.NodesMap = make(map[string]ComponentNode, len(kept))
+	sub.Files = keepIDs(project.Files)
+	sub.EntryPoints = keepIDs(project.EntryPoints)
+	sub.Unreachable = keepIDs(project.Unreachable)
+	sub.Untested = keepIDs(project.Untested)
+	sub.MostComplex = keepIDs(project.MostComplex)
+	sub.TopRanked = keepIDs(project.TopRanked)
+	sub.Stats = ProjectStats{}
+	sub.BarrelChains = nil
+	for _, chain := range project.BarrelChains {
+		if len(keepIDs(chain.Chain)) == len(chain.Chain) {
+			sub.BarrelChains = append(sub.BarrelChains, chain)
+		}
+	}
+	sub.ResolutionWarnings = keepResolutionWarnings(project.ResolutionWarnings, kept)
+	sub.RouteTree = keepRouteTree(project.RouteTree, kept)
+
+	for id := range kept {
+		node := project.NodesMap[id]
+		node.Children = nil
+		node.Imports = keepIDs(node.Imports)
+		node.ImportedBy = keepIDs(node.ImportedBy)
+		node.TransitiveDeps = keepIDs(node.TransitiveDeps)
+		node.TransitiveDependents = keepIDs(node.TransitiveDependents)
+		node.DataRoutes = keepRouteTree(node.DataRoutes, kept)
+
+		edges := []ImportEdge{}
+		for _, edge := range node.ImportEdges {
+			if kept[edge.Target] {
+				edges = append(edges, edge)
+			}
+		}
+		node.ImportEdges = edges
+
+		refs := []ImporterRef{}
+		for _, ref := range node.ImportedByDetails {
+			if kept[ref.Importer] {
+				refs = append(refs, ref)
+			}
+		}
+		node.ImportedByDetails = refs
+
+		sub.NodesMap[id] = node
+		addNodeStats(&sub.Stats, node)
+	}
+
+	markDevEdges(&sub)
+	sub.Clusters = StronglyConnectedComponents(sub)
+	sub.Cycles = classifyCycles(sub.Clusters)
+	sub.Chunks = computeChunks(sub, sub.EntryPoints)
+	sub.ReduxSlices = linkReduxSlices(sub)
+	sub.Stats.ExternalPackages = countExternalPackages(sub.NodesMap)
+	sub.Stats.RenderDepth = renderDepth(sub, sub.EntryPoints)
+	sub.UsageRanking = usageRanking(sub)
+	sub.Stats.AggregateHash = aggregateHash(sub)
+	buildTree(&sub)
+
+	return sub
+}
+
+// NodesByType returns the nodes of the given type, ordered by ID
+func (p Project) NodesByType(t string) []ComponentNode {
+	nodes := []ComponentNode{}
+	for _, id := range sortedNodeIDs(p) {
+		if node := p.NodesMap[id]; node.Type == t {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// FilterByTypes returns the subgraph of nodes whose type is one of types
+func (p Project) FilterByTypes(types ...string) Project {
+	return subgraph(p, func(node ComponentNode) bool {
+		return containsString(types, node.Type)
+	})
+}
+
+// StateGraph returns the state-management flow of the project: state and hook
+// nodes plus the components that import them, with edges restricted accordingly
+func StateGraph(project Project) Project {
+	isState := func(node ComponentNode) bool {
+		return node.Type == "state" || node.Type == "hook"
+	}
+
+	return subgraph(project, func(node ComponentNode) bool {
+		if isState(node) {
+			return true
+		}
+		if node.Type != "component" {
+			return false
+		}
+		for _, importPath := range node.Imports {
+			if target, exists := project.NodesMap[importPath]; exists && isState(target) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ReduxGraph returns the Redux Toolkit structure of the project: slices, the
+// stores combining them, and the files selecting from or importing them
+func ReduxGraph(project Project) Project {
+	keep := make(map[string]bool)
+	for _, slice := range project.ReduxSlices {
+		keep[slice.File] = true
+		if slice.Store != "" {
+			keep[slice.Store] = true
+		}
+		for _, consumer := range slice.Consumers {
+			keep[consumer] = true
+		}
+	}
+
+	return subgraph(project, func(node ComponentNode) bool {
+		return keep[node.ID] || node.ReduxStore
+	})
+}
+
+// FilterByFanInOut returns the architecturally significant part of the
+// project: nodes imported by at least minFanIn files and importing at least
+// minFanOut files, counted over the full graph. Edges to dropped nodes are
+// removed; the full project passed in is left untouched.
+func FilterByFanInOut(project Project, minFanIn, minFanOut int) Project {
+	return subgraph(project, func(node ComponentNode) bool {
+		return scannedCount(project, node.ImportedBy) >= minFanIn &&
+			scannedCount(project, node.Imports) >= minFanOut
+	})
+}
+
+// scannedCount returns the number of distinct scanned nodes among ids
+func scannedCount(project Project, ids []string) int {
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if _, exists := project.NodesMap[id]; exists {
+			seen[id] = true
+		}
+	}
+	return len(seen)
+}
+
+// TransitiveReduction returns the project without the import edges implied by
+// others: A→C is dropped when A also reaches C through another module. Edges
+// between members of one import cycle are kept, and nodes are never removed,
+// so every node reaches the same nodes as before.
+func TransitiveReduction(project Project) Project {
+	// Condense cycles so the remaining graph is acyclic
+	component := make(map[string]string)
+	for id := range project.NodesMap {
+		component[id] = id
+	}
+	for _, cluster := range StronglyConnectedComponents(project) {
+		for _, id := range cluster {
+			component[id] = cluster[0]
+		}
+	}
+	successors := make(map[string]map[string]bool)
+	for id, node := range project.NodesMap {
+		for _, target := range node.Imports {
+			if to, exists := component[target]; exists && to != component[id] {
+				if successors[component[id]] == nil {
+					successors[component[id]] = make(map[string]bool)
+				}
+				successors[component[id]][to] = true
+			}
+		}
+	}
+
+	// descendants returns the components reachable from c through one or more edges
+	descendants := make(map[string]map[string]bool)
+	var reach func(c string) map[string]bool
+	reach = func(c string) map[string]bool {
+		if found, ok := descendants[c]; ok {
+			return found
+		}
+		found := make(map[string]bool)
+		for next := range successors[c] {
+			found[next] = true
+			for further := range reach(next) {
+				found[further] = true
+			}
+		}
+		descendants[c] = found
+		return found
+	}
+
+	// redundant reports whether component to is also reachable from from
+	// through one of its other successors
+	redundant := func(from, to string) bool {
+		for other := range successors[from] {
+			if other != to && reach(other)[to] {
+				return true
+			}
+		}
+		return false
+	}
+
+	reduced := project
+	reduced.NodesMap = make(map[string]ComponentNode, len(project.NodesMap))
+	dropped := make(map[[2]string]bool)
+	for id, node := range project.NodesMap {
+		imports := []string{}
+		for _, target := range node.Imports {
+			if to, exists := component[target]; exists && to != component[id] && redundant(component[id], to) {
+				dropped[[2]string{id, target}] = true
+				continue
+			}
+			imports = append(imports, target)
+		}
+		node.Imports = imports
+		reduced.NodesMap[id] = node
+	}
+
+	for id, node := range reduced.NodesMap {
+		edges := []ImportEdge{}
+		for _, edge := range node.ImportEdges {
+			if !dropped[[2]string{id, edge.Target}] {
+				edges = append(edges, edge)
+			}
+		}
+		node.ImportEdges = edges
+
+		importedBy := []string{}
+		for _, importer := range node.ImportedBy {
+			if !dropped[[2]string{importer, id}] {
+				importedBy = append(importedBy, importer)
+			}
+		}
+		node.ImportedBy = importedBy
+
+		refs := []ImporterRef{}
+		for _, ref := range node.ImportedByDetails {
+			if !dropped[[2]string{ref.Importer, id}] {
+				refs = append(refs, ref)
+			}
+		}
+		node.ImportedByDetails = refs
+		reduced.NodesMap[id] = node
+	}
+
+	// Rebuild the tree and statistics over the remaining edges
+	return subgraph(reduced, func(ComponentNode) bool { return true })
+}
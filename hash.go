@@ -0,0 +1,32 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// fileHash returns the hex SHA-256 of a file's content
+func fileHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// aggregateHash combines the node hashes, with their IDs, in sorted ID order,
+// so identical sources give the same hash however the walk visited them. It
+// is empty when nodes carry no hashes.
+func aggregateHash(project Project) string {
+	h := sha256.New()
+	hashed := false
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		if node.Hash == "" {
+			continue
+		}
+		hashed = true
+		h.Write([]byte(ConvertToUnixPath(id) + "\x00" + node.Hash + "\n"))
+	}
+	if !hashed {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
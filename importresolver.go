@@ -1,68 +1,396 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // AliasConfig holds the project's import alias configuration
 type AliasConfig struct {
-	BaseURL string            // The base URL for resolving imports (e.g., "src")
-	Aliases map[string]string // Map of alias -> actual path
+	BaseURL  string            // The base URL for resolving imports (e.g., "src")
+	BaseDirs []string          // Additional base directories tried in order after BaseURL
+	Aliases  map[string]string // Map of alias -> actual path
+	// Exact aliases map a whole specifier to a single file (tsconfig paths without "/*")
+	ExactAliases map[string]string
+	// RootDirs are directories merged into one virtual tree for relative imports (tsconfig rootDirs)
+	RootDirs []string
+	// ConfigDir is the directory of the tsconfig/jsconfig relative to the project
+	// root ("" for the root itself, ".." when it was found in a parent). Path
+	// targets are relative to it when there is no baseUrl, as in TypeScript 5.
+	ConfigDir string
+	// Inferred maps aliases guessed from the project's own imports rather than
+	// read from configuration to their directory relative to the project root
+	Inferred map[string]string
 }
 
-// ReadProjectConfig reads project configuration files to detect import aliases
+// isAlias reports whether an import specifier is covered by a configured alias
+func (c AliasConfig) isAlias(importPath string) bool {
+	if _, ok := c.ExactAliases[importPath]; ok {
+		return true
+	}
+	_, _, ok := c.matchAlias(importPath)
+	return ok
+}
+
+// matchAlias returns the longest alias that covers the specifier, either
+// exactly or followed by "/", so "~" matches "~/components/Button" but not
+// "~other" and "@app" wins over "@" for "@app/utils"
+func (c AliasConfig) matchAlias(importPath string) (alias, target string, ok bool) {
+	for candidate, candidateTarget := range c.Aliases {
+		trimmed := strings.TrimSuffix(candidate, "/")
+		if trimmed == "" {
+			continue
+		}
+		if importPath != trimmed && !strings.HasPrefix(importPath, trimmed+"/") {
+			continue
+		}
+		if !ok || len(trimmed) > len(alias) {
+			alias, target, ok = trimmed, candidateTarget, true
+		}
+	}
+	return alias, target, ok
+}
+
+// baseDirs returns every base directory for bare specifiers, BaseURL first
+func (c AliasConfig) baseDirs() []string {
+	dirs := []string{}
+	if c.BaseURL != "" {
+		dirs = append(dirs, c.BaseURL)
+	}
+	for _, dir := range c.BaseDirs {
+		if dir != "" && dir != c.BaseURL {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// moduleExtensions lists the extensions tried when an import omits one
+var moduleExtensions = []string{".js", ".jsx", ".ts", ".tsx"}
+
+// moduleExists reports whether path names a module file, either directly,
+// by adding a known extension, or as a directory with an index file
+func moduleExists(src sourceFS, path string) bool {
+	_, ok := moduleFile(src, path)
+	return ok
+}
+
+// moduleFile returns the file an extensionless specifier path refers to,
+// without a platform hint
+func moduleFile(src sourceFS, path string) (string, bool) {
+	return platformModuleFile(src, path, "")
+}
+
+// platformModuleFile returns the file an extensionless specifier path refers
+// to. The precedence mirrors Node and bundlers: the exact file, then the path
+// with a module extension (so foo.ts beats foo/index.ts), then the
+// directory's index. Directories never match as files, and other extensions
+// such as foo.css are not tried. React Native platform variants such as
+// foo.ios.tsx are tried in the order given by platformSuffixes.
+func platformModuleFile(src sourceFS, path, platform string) (string, bool) {
+	isFile := func(candidate string) bool {
+		info, err := src.Stat(candidate)
+		return err == nil && !info.IsDir()
+	}
+
+	if isFile(path) {
+		return path, true
+	}
+	for _, suffixes := range platformSuffixes(platform) {
+		for _, suffix := range suffixes {
+			for _, ext := range moduleExtensions {
+				if isFile(path + suffix + ext) {
+					return path + suffix + ext, true
+				}
+			}
+		}
+		for _, suffix := range suffixes {
+			for _, ext := range moduleExtensions {
+				if index := filepath.Join(path, "index"+suffix+ext); isFile(index) {
+					return index, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// platformSuffixes returns the groups of file name suffixes tried before a
+// module extension. Within a group files come before directory indexes. A
+// platform's own variant wins over the shared one, as in Metro; without a
+// platform, plain files and indexes keep their usual precedence and variants
+// are only a fallback, so foo/index.ts beats foo.native.ts.
+func platformSuffixes(platform string) [][]string {
+	switch platform {
+	case "ios", "android":
+		return [][]string{{"." + platform, ".native", ""}}
+	case "native", "web":
+		return [][]string{{"." + platform, ""}}
+	default:
+		return [][]string{{""}, {".native", ".ios", ".android", ".web"}}
+	}
+}
+
+// foldModulePath matches relPath against the files under rootDir ignoring
+// case, returning the path with its on-disk casing. The last segment may omit
+// a module extension.
+func foldModulePath(src sourceFS, rootDir, relPath string) (string, bool) {
+	parts := strings.Split(filepath.Clean(relPath), string(filepath.Separator))
+	current := ""
+
+	for i, part := range parts {
+		if part == "." || part == ".." {
+			current = filepath.Join(current, part)
+			continue
+		}
+
+		if _, err := src.Stat(filepath.Join(rootDir, current, part)); err == nil {
+			current = filepath.Join(current, part)
+			continue
+		}
+
+		entries, err := src.ReadDir(filepath.Join(rootDir, current))
+		if err != nil {
+			return "", false
+		}
+
+		last := i == len(parts)-1
+		match := ""
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.EqualFold(name, part) {
+				match = name
+				break
+			}
+			if last && !entry.IsDir() && strings.EqualFold(strings.TrimSuffix(name, filepath.Ext(name)), part) &&
+				isModuleExtension(filepath.Ext(name)) {
+				match = name
+			}
+		}
+		if match == "" {
+			return "", false
+		}
+		current = filepath.Join(current, match)
+	}
+
+	return current, true
+}
+
+// isModuleExtension reports whether ext is one of moduleExtensions
+func isModuleExtension(ext string) bool {
+	for _, candidate := range moduleExtensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeAliasConfig combines two alias configurations, such as those read from
+// tsconfig.json and a bundler config. Aliases of override win over those of
+// base for the same specifier, the base directories and rootDirs of override
+// come before those of base, and BaseURL is taken from override when it sets
+// one. Alias targets of either side are rewritten to be relative to the
+// merged base, so they keep pointing where their own configuration meant.
+// Neither argument is modified.
+func MergeAliasConfig(base, override AliasConfig) AliasConfig {
+	merged := base
+	if override.BaseURL != "" {
+		merged.BaseURL = override.BaseURL
+		merged.ConfigDir = override.ConfigDir
+	}
+
+	to := merged.aliasBase()
+	merged.Aliases = mergeAliasMaps(rebaseAliases(base.Aliases, base.aliasBase(), to), rebaseAliases(override.Aliases, override.aliasBase(), to))
+	merged.ExactAliases = mergeAliasMaps(rebaseAliases(base.ExactAliases, base.aliasBase(), to), rebaseAliases(override.ExactAliases, override.aliasBase(), to))
+	if base.Inferred != nil || override.Inferred != nil {
+		merged.Inferred = mergeAliasMaps(base.Inferred, override.Inferred)
+	}
+	merged.BaseDirs = mergeDirs(override.BaseDirs, base.BaseDirs)
+	merged.RootDirs = mergeDirs(override.RootDirs, base.RootDirs)
+	return merged
+}
+
+// aliasBase returns the directory alias targets are relative to: BaseURL, or
+// else the config directory
+func (c AliasConfig) aliasBase() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return c.ConfigDir
+}
+
+// rebaseAliases returns aliases with their targets, relative to the directory
+// from, rewritten relative to the directory to. Absolute targets, and targets
+// that cannot be expressed relative to to, are kept as they are.
+func rebaseAliases(aliases map[string]string, from, to string) map[string]string {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return aliases
+	}
+	rebased := make(map[string]string, len(aliases))
+	for alias, target := range aliases {
+		rebased[alias] = target
+		if filepath.IsAbs(target) {
+			continue
+		}
+		if rel, err := filepath.Rel(filepath.Clean(to), filepath.Join(from, target)); err == nil {
+			rebased[alias] = rel
+		}
+	}
+	return rebased
+}
+
+// mergeAliasMaps returns a new map with the entries of base and override,
+// override winning on conflicts
+func mergeAliasMaps(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for alias, target := range base {
+		merged[alias] = target
+	}
+	for alias, target := range override {
+		merged[alias] = target
+	}
+	return merged
+}
+
+// mergeDirs concatenates directory lists, dropping repeats, and returns nil
+// when both are empty
+func mergeDirs(first, second []string) []string {
+	var merged []string
+	for _, dir := range append(append([]string{}, first...), second...) {
+		if !containsString(merged, dir) {
+			merged = append(merged, dir)
+		}
+	}
+	return merged
+}
+
+// projectConfigFiles lists the files aliases are read from, in order of
+// precedence: tsconfig over jsconfig, both over bundler configs, and all over
+// package.json
+var projectConfigFiles = []string{
+	"tsconfig.json",
+	"jsconfig.json",
+	"webpack.config.js",
+	"craco.config.js",
+	"vite.config.js",
+	"vite.config.ts",
+	".babelrc",
+	"babel.config.js",
+	"package.json", // Some projects define aliases in package.json
+}
+
+// ReadProjectConfig reads project configuration files to detect import
+// aliases, merging the settings of every file found
 func ReadProjectConfig(rootDir string) (AliasConfig, error) {
+	return readProjectConfig(context.Background(), sourceFS{}, rootDir)
+}
+
+// readProjectConfig reads the alias configuration of rootDir from src. It
+// gives up with ctx's error once ctx is done, checking between config files
+// and between the passes over each JS config.
+func readProjectConfig(ctx context.Context, src sourceFS, rootDir string) (AliasConfig, error) {
 	config := AliasConfig{
-		BaseURL: "",
-		Aliases: make(map[string]string),
+		BaseURL:      "",
+		Aliases:      make(map[string]string),
+		ExactAliases: make(map[string]string),
 	}
 
-	// Check for common configuration files
-	configFiles := []string{
-		"jsconfig.json",
-		"tsconfig.json",
-		"webpack.config.js",
-		"craco.config.js",
-		".babelrc",
-		"babel.config.js",
-		"package.json", // Some projects define aliases in package.json
+	// Every config file present is read on its own and merged in, files
+	// earlier in the list taking precedence
+	for _, configFile := range projectConfigFiles {
+		if err := ctx.Err(); err != nil {
+			return config, err
+		}
+		configPath := filepath.Join(rootDir, configFile)
+		if _, err := src.Stat(configPath); err != nil {
+			continue
+		}
+
+		fileConfig := AliasConfig{Aliases: make(map[string]string), ExactAliases: make(map[string]string)}
+		switch filepath.Ext(configFile) {
+		case ".json":
+			if err := parseJSONConfig(src, configPath, &fileConfig); err != nil {
+				continue
+			}
+		case ".js", ".ts":
+			// For JS configs, this would be more complex and might require executing JS
+			// For now, we could look for common patterns but a full solution would
+			// need a JS parser or even Node.js execution
+			if err := parseJSConfig(ctx, src, configPath, &fileConfig); err != nil {
+				return config, err
+			}
+		}
+		config = MergeAliasConfig(fileConfig, config)
 	}
 
-	for _, configFile := range configFiles {
-		configPath := filepath.Join(rootDir, configFile)
-		if _, err := os.Stat(configPath); err == nil {
-			switch filepath.Ext(configFile) {
-			case ".json":
-				err := parseJSONConfig(configPath, &config)
-				if err == nil {
-					return config, nil
-				}
-			case ".js":
-				// For JS configs, this would be more complex and might require executing JS
-				// For now, we could look for common patterns but a full solution would
-				// need a JS parser or even Node.js execution
-				parseJSConfig(configPath, &config)
+	// Scanning a subdirectory such as src picks up the enclosing project's tsconfig
+	if len(config.Aliases) == 0 && len(config.ExactAliases) == 0 && config.BaseURL == "" {
+		if configPath, configDir, ok := ancestorTSConfig(src, rootDir); ok {
+			config.ConfigDir = configDir
+			if err := parseJSONConfig(src, configPath, &config); err == nil {
+				return config, nil
 			}
+			config.ConfigDir = ""
 		}
 	}
 
-	// If no explicit config is found, check for src directory as a common default
-	if _, err := os.Stat(filepath.Join(rootDir, "src")); err == nil {
+	// If no explicit config is found, check for src directory as a common default.
+	// JS config aliases are relative to the project root, so they keep it there.
+	if config.BaseURL != "" || len(config.Aliases) > 0 {
+		return config, nil
+	}
+	if _, err := src.Stat(filepath.Join(rootDir, "src")); err == nil {
 		config.BaseURL = "src"
 	}
 
 	return config, nil
 }
 
+// ancestorTSConfig finds the tsconfig.json or jsconfig.json of the project
+// enclosing rootDir, searching parent directories up to the first one with a
+// package.json. It returns the file and its directory relative to rootDir.
+// A rootDir with its own package.json is a project of its own, so nothing is
+// searched. Only the disk is searched; an fs.FS has nothing above its root.
+func ancestorTSConfig(src sourceFS, rootDir string) (string, string, bool) {
+	if src.fsys != nil {
+		return "", "", false
+	}
+	if _, err := src.Stat(filepath.Join(rootDir, "package.json")); err == nil {
+		return "", "", false
+	}
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return "", "", false
+	}
+
+	for dir := filepath.Dir(absRoot); ; dir = filepath.Dir(dir) {
+		for _, name := range []string{"tsconfig.json", "jsconfig.json"} {
+			configPath := filepath.Join(dir, name)
+			if _, err := src.Stat(configPath); err == nil {
+				rel, err := filepath.Rel(absRoot, dir)
+				return configPath, rel, err == nil
+			}
+		}
+		if _, err := src.Stat(filepath.Join(dir, "package.json")); err == nil || filepath.Dir(dir) == dir {
+			return "", "", false
+		}
+	}
+}
+
 // JSConfig represents the structure of a jsconfig.json or tsconfig.json file
 type JSConfig struct {
 	CompilerOptions struct {
-		BaseURL string              `json:"baseUrl,omitempty"`
-		Paths   map[string][]string `json:"paths,omitempty"`
+		BaseURL  string              `json:"baseUrl,omitempty"`
+		Paths    map[string][]string `json:"paths,omitempty"`
+		RootDirs []string            `json:"rootDirs,omitempty"`
 	} `json:"compilerOptions,omitempty"`
 }
 
@@ -75,8 +403,8 @@ type PackageJSON struct {
 }
 
 // parseJSONConfig parses JSON configuration files for import aliases
-func parseJSONConfig(configPath string, config *AliasConfig) error {
-	data, err := os.ReadFile(configPath)
+func parseJSONConfig(src sourceFS, configPath string, config *AliasConfig) error {
+	data, err := src.ReadFile(configPath)
 	if err != nil {
 		return err
 	}
@@ -85,10 +413,26 @@ func parseJSONConfig(configPath string, config *AliasConfig) error {
 	if strings.HasSuffix(configPath, "jsconfig.json") || strings.HasSuffix(configPath, "tsconfig.json") {
 		var jsConfig JSConfig
 		if err := json.Unmarshal(data, &jsConfig); err == nil {
+			// baseUrl and rootDirs are relative to the config file
 			config.BaseURL = jsConfig.CompilerOptions.BaseURL
+			if config.BaseURL != "" && config.ConfigDir != "" {
+				config.BaseURL = filepath.Join(config.ConfigDir, config.BaseURL)
+			}
+			for _, dir := range jsConfig.CompilerOptions.RootDirs {
+				config.RootDirs = append(config.RootDirs, filepath.Join(config.ConfigDir, filepath.Clean(filepath.FromSlash(dir))))
+			}
 
 			// Process paths (aliases)
 			for aliasPattern, targetPaths := range jsConfig.CompilerOptions.Paths {
+				// Patterns without a wildcard map one specifier to one file
+				if len(targetPaths) > 0 && !strings.HasSuffix(aliasPattern, "*") {
+					if config.ExactAliases == nil {
+						config.ExactAliases = make(map[string]string)
+					}
+					config.ExactAliases[aliasPattern] = targetPaths[0]
+					continue
+				}
+
 				if len(targetPaths) > 0 {
 					// Convert pattern "components/*" to "components/"
 					alias := strings.TrimSuffix(aliasPattern, "/*")
@@ -133,12 +477,13 @@ func parseJSONConfig(configPath string, config *AliasConfig) error {
 	return nil
 }
 
-// parseJSConfig looks for common alias patterns in JS config files
-func parseJSConfig(configPath string, config *AliasConfig) {
+// parseJSConfig looks for common alias patterns in JS config files. The only
+// error it returns is ctx's, checked between its regex passes.
+func parseJSConfig(ctx context.Context, src sourceFS, configPath string, config *AliasConfig) error {
 	// This is a simplified approach - a full solution would need a JS parser
-	data, err := os.ReadFile(configPath)
+	data, err := src.ReadFile(configPath)
 	if err != nil {
-		return
+		return nil
 	}
 
 	content := string(data)
@@ -150,6 +495,9 @@ func parseJSConfig(configPath string, config *AliasConfig) {
 	}
 
 	for _, pattern := range baseUrlPatterns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		re := regexp.MustCompile(pattern)
 		matches := re.FindStringSubmatch(content)
 		if len(matches) > 1 {
@@ -165,12 +513,17 @@ func parseJSConfig(configPath string, config *AliasConfig) {
 	}
 
 	for _, pattern := range aliasPatterns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		re := regexp.MustCompile(pattern)
 		matches := re.FindStringSubmatch(content)
 		if len(matches) > 1 {
 			aliasBlock := matches[1]
 			// Very simple key-value extraction, would miss many cases
-			keyValueRe := regexp.MustCompile(`['"](\w+)['"]:\s*['"]([^'"]+)['"]`)
+			// Keys may be symbols such as "~" or "@"; values may be wrapped in
+			// path.resolve(__dirname, ...) or Vite's fileURLToPath(new URL(...))
+			keyValueRe := regexp.MustCompile(`['"]([\w@~$./-]+)['"]\s*:\s*(?:path\.(?:resolve|join)\(\s*__dirname\s*,\s*|fileURLToPath\(\s*new\s+URL\(\s*)?['"]([^'"]+)['"]`)
 			kvMatches := keyValueRe.FindAllStringSubmatch(aliasBlock, -1)
 
 			for _, kv := range kvMatches {
@@ -180,13 +533,53 @@ func parseJSConfig(configPath string, config *AliasConfig) {
 			}
 		}
 	}
+
+	// Look for webpack resolve.modules, whose local entries act as base directories
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if matches := webpackModulesRegex.FindStringSubmatch(content); len(matches) > 1 {
+		for _, dir := range quotedStringRegex.FindAllStringSubmatch(matches[1], -1) {
+			if dir[1] == "node_modules" || filepath.IsAbs(dir[1]) {
+				continue
+			}
+			config.BaseDirs = append(config.BaseDirs, filepath.Clean(filepath.FromSlash(dir[1])))
+		}
+	}
+	return nil
+}
+
+// webpackModulesRegex matches a webpack resolve.modules array
+var webpackModulesRegex = regexp.MustCompile(`modules\s*:\s*\[([^\]]*)\]`)
+
+// quotedStringRegex matches a single- or double-quoted string literal
+var quotedStringRegex = regexp.MustCompile(`['"]([^'"]+)['"]`)
+
+// inBaseDir reports whether a bare specifier names a module under one of the
+// configured base directories
+func (c AliasConfig) inBaseDir(src sourceFS, importPath, rootDir string) bool {
+	for _, dir := range c.baseDirs() {
+		if moduleExists(src, filepath.Join(rootDir, dir, importPath)) {
+			return true
+		}
+	}
+	return false
 }
 
 // ResolveImportPath resolves an import path using project alias configuration
 func ResolveImportPath(importPath string, config AliasConfig, projectDir string, currentDir string) string {
+	return resolveImportPath(sourceFS{}, importPath, config, projectDir, currentDir)
+}
+
+// resolveImportPath resolves an import path, checking for modules in src
+func resolveImportPath(src sourceFS, importPath string, config AliasConfig, projectDir string, currentDir string) string {
 	// If it's a relative import, resolve it relative to the current file
 	if strings.HasPrefix(importPath, ".") {
-		return filepath.Join(currentDir, importPath)
+		resolved := filepath.Join(currentDir, importPath)
+		if merged, ok := resolveInRootDirs(src, resolved, config, projectDir, currentDir); ok {
+			resolved = merged
+		}
+		return filepath.Join(projectDir, resolved)
 	}
 
 	// If it's an absolute import starting with /, resolve from project root
@@ -194,35 +587,185 @@ func ResolveImportPath(importPath string, config AliasConfig, projectDir string,
 		return filepath.Join(projectDir, importPath[1:])
 	}
 
+	// Exact aliases resolve straight to their mapped file
+	if target, ok := config.ExactAliases[importPath]; ok {
+		return aliasTargetPath(target, config, projectDir)
+	}
+
 	// Check if the import uses an alias
-	for alias, target := range config.Aliases {
-		if strings.HasPrefix(importPath, alias) {
-			// Replace the alias prefix with the target path
-			relativePath := strings.TrimPrefix(importPath, alias)
-			if strings.HasPrefix(relativePath, "/") {
-				relativePath = relativePath[1:]
-			}
+	if alias, target, ok := config.matchAlias(importPath); ok {
+		// Replace the alias prefix with the target path
+		relativePath := strings.TrimPrefix(strings.TrimPrefix(importPath, alias), "/")
 
-			// If the target is an absolute path, use it directly
-			if filepath.IsAbs(target) {
-				return filepath.Join(target, relativePath)
-			}
+		// Resolve from the alias target (absolute, baseURL, or project root)
+		return filepath.Join(aliasTargetPath(target, config, projectDir), relativePath)
+	}
+
+	// If no alias matches, try each base directory in order and use the first
+	// one that contains the module, falling back to the primary base directory
+	baseDirs := config.baseDirs()
+	for _, baseDir := range baseDirs {
+		candidate := filepath.Join(projectDir, baseDir, importPath)
+		if moduleExists(src, candidate) {
+			return candidate
+		}
+	}
+	if len(baseDirs) > 0 {
+		return filepath.Join(projectDir, baseDirs[0], importPath)
+	}
+
+	// As a fallback, try to resolve from project root
+	return filepath.Join(projectDir, importPath)
+}
+
+// resolveInRootDirs retries a relative import that doesn't exist under the
+// importing file's root directory in the sibling rootDirs, as if they were
+// merged into one tree
+func resolveInRootDirs(src sourceFS, resolved string, config AliasConfig, projectDir, currentDir string) (string, bool) {
+	if len(config.RootDirs) < 2 || moduleExists(src, filepath.Join(projectDir, resolved)) {
+		return "", false
+	}
 
-			// If we have a baseURL, resolve from there
-			if config.BaseURL != "" {
-				return filepath.Join(projectDir, config.BaseURL, target, relativePath)
+	for _, home := range config.RootDirs {
+		if !pathWithin(currentDir, home) {
+			continue
+		}
+		rel, err := filepath.Rel(home, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		for _, sibling := range config.RootDirs {
+			candidate := filepath.Join(sibling, rel)
+			if sibling != home && moduleExists(src, filepath.Join(projectDir, candidate)) {
+				return candidate, true
 			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// pathWithin reports whether path is dir or lies below it
+func pathWithin(path, dir string) bool {
+	return dir == "." || path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// AliasProblem describes an alias whose target cannot be used for resolution
+type AliasProblem struct {
+	Alias   string `json:"alias"`
+	Target  string `json:"target"`
+	Path    string `json:"path"`
+	Problem string `json:"problem"` // missing, empty
+}
 
-			// Otherwise resolve from project root
-			return filepath.Join(projectDir, target, relativePath)
+// ValidateAliases checks that every alias target exists on disk and is not empty
+func ValidateAliases(rootDir string, config AliasConfig) []AliasProblem {
+	problems := []AliasProblem{}
+
+	targets := make(map[string]string)
+	for alias, target := range config.Aliases {
+		targets[alias] = target
+	}
+	for alias, target := range config.ExactAliases {
+		targets[alias] = target
+	}
+
+	for alias, target := range targets {
+		targetPath := aliasTargetPath(target, config, rootDir)
+
+		info, err := os.Stat(targetPath)
+		if err != nil {
+			problems = append(problems, AliasProblem{Alias: alias, Target: target, Path: targetPath, Problem: "missing"})
+			continue
+		}
+
+		if info.IsDir() {
+			entries, err := os.ReadDir(targetPath)
+			if err == nil && len(entries) == 0 {
+				problems = append(problems, AliasProblem{Alias: alias, Target: target, Path: targetPath, Problem: "empty"})
+			}
 		}
 	}
 
-	// If no alias matches but we have a baseURL, try resolving from there
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Alias < problems[j].Alias
+	})
+
+	return problems
+}
+
+// aliasTargetPath returns the location on disk an alias target points to
+func aliasTargetPath(target string, config AliasConfig, projectDir string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
 	if config.BaseURL != "" {
-		return filepath.Join(projectDir, config.BaseURL, importPath)
+		return filepath.Join(projectDir, config.BaseURL, target)
 	}
+	return filepath.Join(projectDir, config.ConfigDir, target)
+}
 
-	// As a fallback, try to resolve from project root
-	return filepath.Join(projectDir, importPath)
+// CheckAliasCycles looks for aliases whose targets refer back to an alias
+// specifier, either directly (self-referential) or through a chain of aliases
+func CheckAliasCycles(config AliasConfig) []string {
+	warnings := []string{}
+
+	targets := make(map[string]string)
+	for alias, target := range config.Aliases {
+		targets[alias] = filepath.ToSlash(filepath.Clean(target))
+	}
+	for alias, target := range config.ExactAliases {
+		targets[alias] = filepath.ToSlash(filepath.Clean(target))
+	}
+
+	// matchingAlias returns the alias a target path would itself be resolved through
+	matchingAlias := func(target string) (string, bool) {
+		for alias := range targets {
+			if target == alias || strings.HasPrefix(target, alias+"/") {
+				return alias, true
+			}
+		}
+		return "", false
+	}
+
+	aliases := make([]string, 0, len(targets))
+	for alias := range targets {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		target := targets[alias]
+		if target == alias || strings.HasPrefix(target, alias+"/") {
+			warnings = append(warnings, fmt.Sprintf("alias %q points to %q, which refers to the alias itself", alias, target))
+			continue
+		}
+
+		// Follow the chain of aliases and report if it leads back to the start
+		chain := []string{alias}
+		visited := map[string]bool{alias: true}
+		current := target
+		for {
+			next, ok := matchingAlias(current)
+			if !ok {
+				break
+			}
+			chain = append(chain, next)
+			if next == alias {
+				quoted := make([]string, len(chain))
+				for i, name := range chain {
+					quoted[i] = strconv.Quote(name)
+				}
+				warnings = append(warnings, fmt.Sprintf("alias %q is circular: %s", alias, strings.Join(quoted, " -> ")))
+				break
+			}
+			if visited[next] {
+				break
+			}
+			visited[next] = true
+			current = targets[next]
+		}
+	}
+
+	return warnings
 }
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// mapSource returns a source filesystem rooted at "app" holding empty files
+// at the given slash-separated paths
+func mapSource(paths ...string) sourceFS {
+	fsys := fstest.MapFS{}
+	for _, path := range paths {
+		fsys[path] = &fstest.MapFile{}
+	}
+	return newSourceFS("app", fsys)
+}
+
+func TestResolveImportPathBaseDirs(t *testing.T) {
+	src := mapSource("src/App.js", "shared/theme/index.js", "src/utils.js", "shared/utils.js")
+	config := AliasConfig{BaseURL: "src", BaseDirs: []string{"shared"}}
+
+	tests := []struct {
+		specifier string
+		want      string
+	}{
+		{"theme", "shared/theme"},  // only in the second base directory
+		{"utils", "src/utils"},     // in both; BaseURL comes first
+		{"missing", "src/missing"}, // nowhere; falls back to the first
+	}
+	for _, tt := range tests {
+		got := resolveImportPath(src, tt.specifier, config, "app", "src")
+		if want := filepath.Join("app", filepath.FromSlash(tt.want)); got != want {
+			t.Errorf("resolveImportPath(%q) = %q, want %q", tt.specifier, got, want)
+		}
+	}
+}
+
+func TestExactPathAlias(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"tsconfig.json":       `{"compilerOptions": {"baseUrl": ".", "paths": {"config": ["src/config/index.ts"], "@/*": ["src/*"]}}}`,
+		"src/config/index.ts": "export const apiURL = '/api'\n",
+		"src/App.tsx":         "import { apiURL } from 'config'\nimport { apiURL as other } from '@/config'\n",
+	}, ScanOptions{})
+
+	if _, ok := edgeTo(t, project, "src/App.tsx", "src/config/index.ts"); !ok {
+		t.Errorf("App.tsx imports %v, want src/config/index.ts", nodeAt(t, project, "src/App.tsx").Imports)
+	}
+	if externals := nodeAt(t, project, "src/App.tsx").Externals; len(externals) != 0 {
+		t.Errorf("externals = %v, want none", externals)
+	}
+}
+
+func TestCaseInsensitiveResolution(t *testing.T) {
+	files := map[string]string{
+		"src/App.jsx":             "import Button from './button'\nimport Card from './Components/card'\n",
+		"src/Button.tsx":          "export default function Button() { return <button /> }\n",
+		"src/components/Card.jsx": "export default function Card() { return <div /> }\n",
+	}
+
+	project := scanFiles(t, files, ScanOptions{CaseInsensitive: true})
+	for _, target := range []string{"src/Button.tsx", "src/components/Card.jsx"} {
+		if _, ok := edgeTo(t, project, "src/App.jsx", target); !ok {
+			t.Errorf("App.jsx imports %v, want %s", nodeAt(t, project, "src/App.jsx").Imports, target)
+		}
+	}
+
+	project = scanFiles(t, files, ScanOptions{})
+	if _, ok := edgeTo(t, project, "src/App.jsx", "src/Button.tsx"); ok {
+		t.Error("./button resolved to Button.tsx without CaseInsensitive")
+	}
+}
+
+func TestRootDirsMerging(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"tsconfig.json":               `{"compilerOptions": {"rootDirs": ["src", "generated"]}}`,
+		"src/views/Home.tsx":          "import { messages } from './messages'\nimport { Nav } from '../Nav'\n",
+		"src/Nav.tsx":                 "export const Nav = () => <nav />\n",
+		"generated/views/messages.ts": "export const messages = {}\n",
+	}, ScanOptions{})
+
+	for _, target := range []string{"generated/views/messages.ts", "src/Nav.tsx"} {
+		if _, ok := edgeTo(t, project, "src/views/Home.tsx", target); !ok {
+			t.Errorf("Home.tsx imports %v, want %s", nodeAt(t, project, "src/views/Home.tsx").Imports, target)
+		}
+	}
+}
+
+func TestWebpackResolveModules(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"webpack.config.js":     "module.exports = {\n  resolve: {\n    modules: ['node_modules', 'src', 'shared'],\n  },\n}\n",
+		"src/App.jsx":           "import theme from 'theme'\nimport Header from 'Header'\nimport React from 'react'\n",
+		"src/Header.jsx":        "export default function Header() { return <header /> }\n",
+		"shared/theme/index.js": "export default { color: 'red' }\n",
+	}, ScanOptions{})
+
+	for _, target := range []string{"shared/theme/index.js", "src/Header.jsx"} {
+		if _, ok := edgeTo(t, project, "src/App.jsx", target); !ok {
+			t.Errorf("App.jsx imports %v, want %s", nodeAt(t, project, "src/App.jsx").Imports, target)
+		}
+	}
+	if externals := nodeAt(t, project, "src/App.jsx").Externals; len(externals) != 1 || externals[0] != "react" {
+		t.Errorf("externals = %v, want [react]", externals)
+	}
+}
+
+func TestAliasOutsideRoot(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"shared/src/format.ts": "export const format = (s) => s\n",
+		"app/tsconfig.json":    `{"compilerOptions": {"baseUrl": ".", "paths": {"@shared/*": ["../shared/src/*"]}}}`,
+		"app/src/App.tsx":      "import { format } from '@shared/format'\nimport { missing } from '@shared/missing'\n",
+	})
+
+	project := scanDir(t, filepath.Join(dir, "app"), ScanOptions{})
+
+	// The alias target directory is scanned, so its files link
+	if edge, ok := edgeTo(t, project, "src/App.tsx", "../shared/src/format.ts"); !ok || edge.OutOfRoot {
+		t.Errorf("edge to format.ts = %+v, %v; want a scanned edge", edge, ok)
+	}
+	nodeAt(t, project, "../shared/src/format.ts")
+
+	// Files missing there are tagged out of root rather than dropped
+	if edge, ok := edgeTo(t, project, "src/App.tsx", "../shared/src/missing"); !ok || !edge.OutOfRoot {
+		t.Errorf("edge to missing = %+v, %v; want an out-of-root edge", edge, ok)
+	}
+	if project.Stats.OutOfRootEdges != 1 {
+		t.Errorf("out-of-root edges = %d, want 1", project.Stats.OutOfRootEdges)
+	}
+	warned := false
+	for _, warning := range project.ScanWarnings {
+		warned = warned || strings.Contains(warning, "outside")
+	}
+	if !warned {
+		t.Errorf("warnings = %q, want one about imports outside the root", project.ScanWarnings)
+	}
+}
+
+func TestModuleFilePrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		specifier string
+		files     []string
+		want      string // "" when nothing matches
+	}{
+		{"exact file", "src/foo.js", []string{"src/foo.js", "src/foo.js.ts", "src/foo.js/index.ts"}, "src/foo.js"},
+		{"extension before index", "src/foo", []string{"src/foo.ts", "src/foo/index.ts"}, "src/foo.ts"},
+		{"extension order", "src/foo", []string{"src/foo.tsx", "src/foo.ts", "src/foo.js"}, "src/foo.js"},
+		{"directory index", "src/foo", []string{"src/foo/index.jsx", "src/foo/Bar.tsx"}, "src/foo/index.jsx"},
+		{"css is not a module", "src/foo", []string{"src/foo.css"}, ""},
+		{"css beside index", "src/foo", []string{"src/foo.css", "src/foo/index.js"}, "src/foo/index.js"},
+		{"directory without index", "src/foo", []string{"src/foo/Bar.tsx"}, ""},
+	}
+	for _, tt := range tests {
+		got, ok := moduleFile(mapSource(tt.files...), filepath.Join("app", filepath.FromSlash(tt.specifier)))
+		want := ""
+		if tt.want != "" {
+			want = filepath.Join("app", filepath.FromSlash(tt.want))
+		}
+		if got != want || ok != (tt.want != "") {
+			t.Errorf("%s: moduleFile = %q, %v; want %q", tt.name, got, ok, want)
+		}
+	}
+}
+
+func TestAncestorTSConfig(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json":       `{"name": "monorepo"}`,
+		"tsconfig.json":      `{"compilerOptions": {"baseUrl": ".", "paths": {"#app/*": ["web/src/*"]}}}`,
+		"web/src/App.tsx":    "import { api } from '#app/api'\n",
+		"web/src/api.ts":     "export const api = {}\n",
+		"admin/package.json": `{"name": "admin"}`,
+		"admin/src/App.tsx":  "import { api } from '#app/api'\n",
+		"admin/src/api.ts":   "export const api = {}\n",
+	})
+
+	// Without a package.json of its own, web uses the enclosing tsconfig
+	project := scanDir(t, filepath.Join(dir, "web"), ScanOptions{})
+	if _, ok := edgeTo(t, project, "src/App.tsx", "src/api.ts"); !ok {
+		t.Errorf("web: App.tsx imports %v, want src/api.ts", nodeAt(t, project, "src/App.tsx").Imports)
+	}
+
+	// admin is a package of its own, so the monorepo's aliases don't apply
+	project = scanDir(t, filepath.Join(dir, "admin"), ScanOptions{})
+	for _, target := range nodeAt(t, project, "src/App.tsx").Imports {
+		if strings.Contains(filepath.ToSlash(target), "web/") {
+			t.Errorf("admin: App.tsx imports %s through the monorepo alias", target)
+		}
+	}
+}
+
+func TestAliasOverridePrecedence(t *testing.T) {
+	files := map[string]string{
+		"tsconfig.json":             `{"compilerOptions": {"baseUrl": ".", "paths": {"@ui/*": ["src/legacy/*"], "@lib/*": ["src/lib/*"]}}}`,
+		"src/App.tsx":               "import { Button } from '@ui/Button'\nimport { fmt } from '@lib/fmt'\nimport { theme } from 'theme'\n",
+		"src/legacy/Button.tsx":     "export const Button = () => <button />\n",
+		"src/components/Button.tsx": "export const Button = () => <button />\n",
+		"src/lib/fmt.ts":            "export const fmt = (s) => s\n",
+		"theme.ts":                  "export const theme = {}\n",
+		"shared/theme.ts":           "export const theme = {}\n",
+	}
+
+	tests := []struct {
+		name     string
+		override *AliasConfig
+		targets  []string
+	}{
+		{"detected", nil, []string{"src/legacy/Button.tsx", "src/lib/fmt.ts", "theme.ts"}},
+		{"alias", &AliasConfig{Aliases: map[string]string{"@ui": "src/components"}}, []string{"src/components/Button.tsx", "src/lib/fmt.ts", "theme.ts"}},
+		{"base URL", &AliasConfig{BaseURL: "shared"}, []string{"src/legacy/Button.tsx", "src/lib/fmt.ts", "shared/theme.ts"}},
+	}
+	for _, tt := range tests {
+		project := scanFiles(t, files, ScanOptions{AliasOverride: tt.override})
+		for _, target := range tt.targets {
+			if _, ok := edgeTo(t, project, "src/App.tsx", target); !ok {
+				t.Errorf("%s: App.tsx imports %v, want %s", tt.name, nodeAt(t, project, "src/App.tsx").Imports, target)
+			}
+		}
+	}
+}
+
+func TestMergeAliasConfig(t *testing.T) {
+	base := AliasConfig{
+		BaseURL:      "src",
+		Aliases:      map[string]string{"@ui": "legacy", "@lib": "lib"},
+		ExactAliases: map[string]string{"config": "config.ts"},
+		BaseDirs:     []string{"src", "shared"},
+		RootDirs:     []string{"src"},
+	}
+	override := AliasConfig{
+		Aliases:  map[string]string{"@ui": "src/components"},
+		BaseDirs: []string{"vendor", "src"},
+		RootDirs: []string{"generated"},
+	}
+
+	// Targets stay where their own configuration put them: base ones under
+	// src, override ones under the root, which has no BaseURL of its own
+	merged := MergeAliasConfig(base, override)
+	for alias, want := range map[string]string{
+		"@ui":    "src/components",
+		"@lib":   "src/lib",
+		"config": "src/config.ts",
+	} {
+		target, ok := merged.Aliases[alias]
+		if !ok {
+			target = merged.ExactAliases[alias]
+		}
+		if got := aliasTargetPath(target, merged, "app"); got != filepath.Join("app", filepath.FromSlash(want)) {
+			t.Errorf("%s resolves to %s, want app/%s", alias, got, want)
+		}
+	}
+	if merged.BaseURL != "src" {
+		t.Errorf("BaseURL = %q, want src", merged.BaseURL)
+	}
+	if want := []string{"vendor", "src", "shared"}; !reflect.DeepEqual(merged.BaseDirs, want) {
+		t.Errorf("BaseDirs = %v, want %v", merged.BaseDirs, want)
+	}
+	if want := []string{"generated", "src"}; !reflect.DeepEqual(merged.RootDirs, want) {
+		t.Errorf("RootDirs = %v, want %v", merged.RootDirs, want)
+	}
+	if base.Aliases["@ui"] != "legacy" || len(base.BaseDirs) != 2 {
+		t.Errorf("MergeAliasConfig modified base: %+v", base)
+	}
+
+	// A BaseURL in the override moves bare imports, not the detected aliases
+	override.BaseURL = "shared"
+	override.Aliases = map[string]string{"@ui": "components"}
+	merged = MergeAliasConfig(base, override)
+	if merged.BaseURL != "shared" {
+		t.Errorf("BaseURL = %q, want shared", merged.BaseURL)
+	}
+	for alias, want := range map[string]string{
+		"@ui":  "shared/components",
+		"@lib": "src/lib",
+	} {
+		if got := aliasTargetPath(merged.Aliases[alias], merged, "app"); got != filepath.Join("app", filepath.FromSlash(want)) {
+			t.Errorf("with BaseURL shared: %s resolves to %s, want app/%s", alias, got, want)
+		}
+	}
+}
+
+func TestViteConfigAliases(t *testing.T) {
+	for _, configFile := range []string{"vite.config.js", "vite.config.ts"} {
+		project := scanFiles(t, map[string]string{
+			configFile: `import path from 'path'
+import { fileURLToPath, URL } from 'node:url'
+import { defineConfig } from 'vite'
+
+export default defineConfig({
+  resolve: {
+    alias: {
+      '@ui': path.resolve(__dirname, './src/components'),
+      '~': fileURLToPath(new URL('./src', import.meta.url)),
+    },
+  },
+})
+`,
+			"src/App.tsx":               "import { Button } from '@ui/Button'\nimport { fmt } from '~/lib/fmt'\n",
+			"src/components/Button.tsx": "export const Button = () => <button />\n",
+			"src/lib/fmt.ts":            "export const fmt = (s) => s\n",
+		}, ScanOptions{})
+
+		for _, target := range []string{"src/components/Button.tsx", "src/lib/fmt.ts"} {
+			if _, ok := edgeTo(t, project, "src/App.tsx", target); !ok {
+				t.Errorf("%s: App.tsx imports %v, want %s", configFile, nodeAt(t, project, "src/App.tsx").Imports, target)
+			}
+		}
+	}
+}
+
+func TestConfigFilesMerged(t *testing.T) {
+	// package.json aliases are relative to the root whatever tsconfig's baseUrl
+	for _, tsconfig := range []string{
+		`{"compilerOptions": {"baseUrl": ".", "paths": {"@ui/*": ["src/components/*"]}}}`,
+		`{"compilerOptions": {"baseUrl": "src", "paths": {"@ui/*": ["components/*"]}}}`,
+	} {
+		project := scanFiles(t, map[string]string{
+			"tsconfig.json":             tsconfig,
+			"package.json":              `{"name": "app", "alias": {"@lib": "src/lib"}}`,
+			"src/App.tsx":               "import { Button } from '@ui/Button'\nimport { fmt } from '@lib/fmt'\n",
+			"src/components/Button.tsx": "export const Button = () => <button />\n",
+			"src/lib/fmt.ts":            "export const fmt = (s) => s\n",
+		}, ScanOptions{})
+
+		for _, target := range []string{"src/components/Button.tsx", "src/lib/fmt.ts"} {
+			if _, ok := edgeTo(t, project, "src/App.tsx", target); !ok {
+				t.Errorf("%s: App.tsx imports %v, want %s", tsconfig, nodeAt(t, project, "src/App.tsx").Imports, target)
+			}
+		}
+	}
+}
+
+func TestConfigFilePrecedence(t *testing.T) {
+	configs := []struct {
+		file, content, target string
+	}{
+		{"tsconfig.json", `{"compilerOptions": {"paths": {"@x/*": ["ts/*"]}}}`, "ts/Button.tsx"},
+		{"jsconfig.json", `{"compilerOptions": {"paths": {"@x/*": ["js/*"]}}}`, "js/Button.tsx"},
+		{"vite.config.js", "export default { resolve: { alias: { '@x': path.resolve(__dirname, 'vite') } } }\n", "vite/Button.tsx"},
+		{"package.json", `{"name": "app", "alias": {"@x": "pkg"}}`, "pkg/Button.tsx"},
+	}
+
+	// Drop the winning config file each round so the next one takes over
+	for i, config := range configs {
+		files := map[string]string{
+			"src/App.tsx": "import { Button } from '@x/Button'\n",
+		}
+		for _, c := range configs {
+			files[c.target] = "export const Button = () => <button />\n"
+		}
+		for _, c := range configs[i:] {
+			files[c.file] = c.content
+		}
+
+		project := scanFiles(t, files, ScanOptions{})
+		if _, ok := edgeTo(t, project, "src/App.tsx", config.target); !ok {
+			t.Errorf("%s: App.tsx imports %v, want %s", config.file, nodeAt(t, project, "src/App.tsx").Imports, config.target)
+		}
+	}
+}
+
+func TestPlatformModuleFile(t *testing.T) {
+	files := []string{
+		"src/Button.ios.tsx", "src/Button.android.tsx", "src/Button.native.tsx", "src/Button.tsx",
+		"src/theme/index.ts", "src/theme.native.ts",
+		"src/Map.ios.tsx", "src/Map.web.tsx",
+		"src/picker/index.native.tsx",
+	}
+	tests := []struct {
+		specifier, platform, want string
+	}{
+		{"src/Button", "", "src/Button.tsx"},
+		{"src/Button", "ios", "src/Button.ios.tsx"},
+		{"src/Button", "android", "src/Button.android.tsx"},
+		{"src/Button", "web", "src/Button.tsx"},
+		{"src/theme", "", "src/theme/index.ts"}, // the index beats a variant without a platform
+		{"src/theme", "native", "src/theme.native.ts"},
+		{"src/Map", "", "src/Map.ios.tsx"},
+		{"src/Map", "web", "src/Map.web.tsx"},
+		{"src/Map", "android", ""},
+		{"src/picker", "", "src/picker/index.native.tsx"},
+		{"src/picker", "ios", "src/picker/index.native.tsx"},
+	}
+
+	src := mapSource(files...)
+	for _, tt := range tests {
+		got, ok := platformModuleFile(src, filepath.Join("app", filepath.FromSlash(tt.specifier)), tt.platform)
+		want := ""
+		if tt.want != "" {
+			want = filepath.Join("app", filepath.FromSlash(tt.want))
+		}
+		if got != want || ok != (tt.want != "") {
+			t.Errorf("%s on %q = %q, %v; want %q", tt.specifier, tt.platform, got, ok, want)
+		}
+	}
+}
+
+func TestAliasIntoNodeModules(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"tsconfig.json":                          `{"compilerOptions": {"baseUrl": ".", "paths": {"@utils/*": ["node_modules/@org/utils/src/*"]}}}`,
+		"src/App.tsx":                            "import { slugify } from '@utils/strings'\nimport { merge } from '@org/utils'\n",
+		"node_modules/@org/utils/src/strings.ts": "import { lower } from './case'\nexport const slugify = (s) => lower(s)\n",
+		"node_modules/@org/utils/src/case.ts":    "export const lower = (s) => s.toLowerCase()\n",
+		"node_modules/@org/utils/src/unused.ts":  "export const unused = 1\n",
+		"node_modules/@org/utils/package.json":   `{"name": "@org/utils"}`,
+	}, ScanOptions{})
+
+	module := "node_modules/@org/utils/src/strings.ts"
+	if _, ok := edgeTo(t, project, "src/App.tsx", module); !ok {
+		t.Errorf("App.tsx imports %v, want %s", nodeAt(t, project, "src/App.tsx").Imports, module)
+	}
+	if _, ok := edgeTo(t, project, module, "node_modules/@org/utils/src/case.ts"); !ok {
+		t.Errorf("strings.ts imports %v, want case.ts", nodeAt(t, project, module).Imports)
+	}
+	if _, scanned := project.NodesMap[filepath.FromSlash("node_modules/@org/utils/src/unused.ts")]; scanned {
+		t.Error("unimported node_modules file was scanned")
+	}
+	if externals := nodeAt(t, project, "src/App.tsx").Externals; len(externals) != 1 || externals[0] != "@org/utils" {
+		t.Errorf("externals = %v, want the bare @org/utils import", externals)
+	}
+}
+
+func TestOutsideSourceRoots(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"proj/tsconfig.json":     `{"compilerOptions": {"baseUrl": ".", "rootDirs": ["src", "../generated"]}}`,
+		"proj/package.json":      `{"name": "proj"}`,
+		"proj/src/App.tsx":       "import { messages } from './messages'\nimport { Nav } from 'src/Nav'\n",
+		"proj/src/Nav.tsx":       "export const Nav = () => <nav />\n",
+		"proj/scripts/build.js":  "export const build = () => {}\n",
+		"proj/server/index.js":   "export const serve = () => {}\n",
+		"generated/messages.ts":  "import { Nav } from '../proj/src/Nav'\nexport const messages = {}\n",
+		"generated/unrelated.ts": "export const unrelated = 1\n",
+	})
+
+	// The enclosing tsconfig makes ".." a base directory of the src scan, but
+	// base directories are never walked, so scripts and server stay out
+	project := scanDir(t, filepath.Join(dir, "proj", "src"), ScanOptions{})
+	for _, id := range sortedNodeIDs(project) {
+		if strings.Contains(filepath.ToSlash(id), "scripts/") || strings.Contains(filepath.ToSlash(id), "server/") {
+			t.Errorf("scanning src picked up %s", id)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, file := range project.Files {
+		if seen[file] {
+			t.Errorf("Files lists %s twice", file)
+		}
+		seen[file] = true
+	}
+
+	// A relative import crosses from one source root into the other and back
+	project = scanDir(t, filepath.Join(dir, "proj"), ScanOptions{})
+	edge, ok := edgeTo(t, project, "src/App.tsx", "../generated/messages.ts")
+	if !ok || edge.OutOfRoot {
+		t.Errorf("App.tsx edges %+v, want a scanned edge to ../generated/messages.ts", nodeAt(t, project, "src/App.tsx").ImportEdges)
+	}
+	if _, ok := edgeTo(t, project, "../generated/messages.ts", "src/Nav.tsx"); !ok {
+		t.Errorf("messages.ts imports %v, want src/Nav.tsx", nodeAt(t, project, "../generated/messages.ts").Imports)
+	}
+	if len(project.Files) != 6 {
+		t.Errorf("Files = %v, want the 4 project files and the 2 generated ones", project.Files)
+	}
+}
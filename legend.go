@@ -0,0 +1,61 @@
+package main
+
+// NodeStyle describes how nodes of a given type should be rendered
+type NodeStyle struct {
+	Color string `json:"color"`
+	Shape string `json:"shape"` // circle, box, diamond, hexagon, ellipse
+}
+
+// defaultTypeStyles is the shared styling for the built-in node types,
+// matching the colours used by the graph view
+var defaultTypeStyles = map[string]NodeStyle{
+	"root":      {Color: "#2c3e50", Shape: "box"},
+	"directory": {Color: "#8e44ad", Shape: "box"},
+	"component": {Color: "#4a90e2", Shape: "circle"},
+	"state":     {Color: "#e67e22", Shape: "diamond"},
+	"util":      {Color: "#2ecc71", Shape: "ellipse"},
+	"hook":      {Color: "#1abc9c", Shape: "hexagon"},
+	"route":     {Color: "#9b59b6", Shape: "box"},
+	"style":     {Color: "#e84393", Shape: "ellipse"},
+	"test":      {Color: "#95a5a6", Shape: "ellipse"},
+	"external":  {Color: "#7f8c8d", Shape: "box"},
+	"icon":      {Color: "#f1c40f", Shape: "circle"},
+	"config":    {Color: "#bdc3c7", Shape: "box"},
+}
+
+// fallbackNodeStyle is used for node types without a configured style
+var fallbackNodeStyle = NodeStyle{Color: "#95a5a6", Shape: "circle"}
+
+// buildLegend returns the style for every node type, applying overrides on top
+// of the defaults and covering any custom types found in the project
+func buildLegend(project Project, overrides map[string]NodeStyle) map[string]NodeStyle {
+	legend := make(map[string]NodeStyle, len(defaultTypeStyles))
+	for nodeType, style := range defaultTypeStyles {
+		legend[nodeType] = style
+	}
+
+	for _, node := range project.NodesMap {
+		if _, ok := legend[node.Type]; !ok {
+			legend[node.Type] = fallbackNodeStyle
+		}
+	}
+
+	for nodeType, style := range overrides {
+		base := legend[nodeType]
+		if style.Color != "" {
+			base.Color = style.Color
+		}
+		if style.Shape != "" {
+			base.Shape = style.Shape
+		}
+		if base.Shape == "" {
+			base.Shape = fallbackNodeStyle.Shape
+		}
+		if base.Color == "" {
+			base.Color = fallbackNodeStyle.Color
+		}
+		legend[nodeType] = base
+	}
+
+	return legend
+}
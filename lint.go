@@ -0,0 +1,74 @@
+package main
+
+import "sort"
+
+// LintThresholds configures when a node counts as a god component. A node is
+// flagged only when it exceeds all three size limits at once.
+type LintThresholds struct {
+	MaxFanIn  int `json:"maxFanIn"`  // number of project files importing the node
+	MaxFanOut int `json:"maxFanOut"` // number of project files the node imports
+	MaxLOC    int `json:"maxLoc"`    // non-blank lines of code
+
+	// MaxBarrelDepth is the longest re-export chain allowed before it is
+	// flagged, as deep barrels hurt tree-shaking
+	MaxBarrelDepth int `json:"maxBarrelDepth"`
+}
+
+// DefaultLintThresholds returns the thresholds used by App.LintProject
+func DefaultLintThresholds() LintThresholds {
+	return LintThresholds{MaxFanIn: 10, MaxFanOut: 10, MaxLOC: 300, MaxBarrelDepth: 2}
+}
+
+// GodComponent is a node that is both large and heavily connected
+type GodComponent struct {
+	ID     string `json:"id"`
+	FanIn  int    `json:"fanIn"`
+	FanOut int    `json:"fanOut"`
+	LOC    int    `json:"loc"`
+}
+
+// LintReport collects the findings of LintProject
+type LintReport struct {
+	Thresholds    LintThresholds `json:"thresholds"`
+	GodComponents []GodComponent `json:"godComponents"`
+
+	// DeepBarrelChains are re-export chains longer than MaxBarrelDepth
+	DeepBarrelChains []BarrelChain `json:"deepBarrelChains"`
+}
+
+// LintProject flags nodes exceeding the fan-in, fan-out and LOC thresholds,
+// ordered from most to least imported, and re-export chains deeper than
+// MaxBarrelDepth
+func LintProject(project Project, thresholds LintThresholds) LintReport {
+	report := LintReport{Thresholds: thresholds, GodComponents: []GodComponent{}, DeepBarrelChains: []BarrelChain{}}
+
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		candidate := GodComponent{
+			ID:     id,
+			FanIn:  scannedCount(project, node.ImportedBy),
+			FanOut: scannedCount(project, node.Imports),
+			LOC:    node.Complexity.LOC,
+		}
+		if candidate.FanIn > thresholds.MaxFanIn &&
+			candidate.FanOut > thresholds.MaxFanOut &&
+			candidate.LOC > thresholds.MaxLOC {
+			report.GodComponents = append(report.GodComponents, candidate)
+		}
+	}
+
+	for _, chain := range project.BarrelChains {
+		if chain.Length > thresholds.MaxBarrelDepth {
+			report.DeepBarrelChains = append(report.DeepBarrelChains, chain)
+		}
+	}
+
+	sort.SliceStable(report.GodComponents, func(i, j int) bool {
+		a, b := report.GodComponents[i], report.GodComponents[j]
+		if a.FanIn != b.FanIn {
+			return a.FanIn > b.FanIn
+		}
+		return a.LOC > b.LOC
+	})
+	return report
+}
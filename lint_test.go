@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLintProjectFanOut(t *testing.T) {
+	project := Project{NodesMap: map[string]ComponentNode{}}
+	hub := ComponentNode{ID: "Hub.jsx", Complexity: Complexity{LOC: 500}}
+	for i := 0; i < 3; i++ {
+		leaf := fmt.Sprintf("Leaf%d.jsx", i)
+		user := fmt.Sprintf("User%d.jsx", i)
+		project.NodesMap[leaf] = ComponentNode{ID: leaf}
+		project.NodesMap[user] = ComponentNode{ID: user, Imports: []string{"Hub.jsx"}}
+		// Each leaf is imported twice, e.g. statically and lazily
+		hub.Imports = append(hub.Imports, leaf, leaf)
+		hub.ImportedBy = append(hub.ImportedBy, user)
+	}
+	// Unresolved and external targets are not project files
+	hub.Imports = append(hub.Imports, "src/missing.js", "src/gone.js")
+	project.NodesMap["Hub.jsx"] = hub
+
+	report := LintProject(project, LintThresholds{MaxFanIn: 2, MaxFanOut: 3, MaxLOC: 300})
+	if len(report.GodComponents) != 0 {
+		t.Errorf("god components = %+v; Hub.jsx imports only 3 project files", report.GodComponents)
+	}
+
+	report = LintProject(project, LintThresholds{MaxFanIn: 2, MaxFanOut: 2, MaxLOC: 300})
+	want := GodComponent{ID: "Hub.jsx", FanIn: 3, FanOut: 3, LOC: 500}
+	if len(report.GodComponents) != 1 || report.GodComponents[0] != want {
+		t.Errorf("god components = %+v, want [%+v]", report.GodComponents, want)
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"embed"
+	"os"
+	"strings"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -12,6 +15,49 @@ import (
 var assets embed.FS
 
 func main() {
+	// Run as a headless HTTP server: react-viz serve <dir> [addr]
+	if len(os.Args) > 2 && os.Args[1] == "serve" {
+		addr := "localhost:34116"
+		if len(os.Args) > 3 {
+			addr = os.Args[3]
+		}
+		if err := Serve(context.Background(), addr, os.Args[2]); err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Print the project JSON: react-viz json <dir> [--stdout-only]
+	if len(os.Args) > 2 && os.Args[1] == "json" {
+		opts := DefaultScanOptions()
+		opts.Quiet = true
+		opts.NoSave = len(os.Args) > 3 && os.Args[3] == "--stdout-only"
+		jsonData, err := GetProjectJSONWithOptions(os.Args[2], opts)
+		if err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		os.Stdout.WriteString(jsonData + "\n")
+		return
+	}
+
+	// Fail a pre-commit hook on cycles through staged files: react-viz staged <dir>
+	if len(os.Args) > 2 && os.Args[1] == "staged" {
+		project, err := ScanStaged(os.Args[2], DefaultScanOptions())
+		if err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		for _, cycle := range project.Cycles {
+			println("Import cycle:", strings.Join(cycle.Nodes, " -> "))
+		}
+		if len(project.Cycles) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ScanOptions configures optional behaviour of a project scan
+type ScanOptions struct {
+	// Classify, when set, is consulted before the built-in heuristics to
+	// determine a file's node type. Returning ok=false falls back to the defaults.
+	Classify func(path, content string) (nodeType string, ok bool)
+
+	// FollowSymlinks descends into symlinked directories during the walk
+	FollowSymlinks bool
+
+	// LogMetrics logs a summary of the scan timings once the scan completes
+	LogMetrics bool
+
+	// SkipDirs lists extra directory names to skip at any depth, in addition to
+	// node_modules, build, dist and hidden directories
+	SkipDirs []string
+
+	// ReplaceSkipDirs makes SkipDirs replace the default skip list instead of extending it
+	ReplaceSkipDirs bool
+
+	// Entries lists entry files (relative to the root) for reachability analysis.
+	// When empty, entries are detected from common conventions.
+	Entries []string
+
+	// TypeStyles overrides the default legend styling per node type
+	TypeStyles map[string]NodeStyle
+
+	// ComputeClosures precomputes each node's transitive dependencies and
+	// dependents for instant impact queries. This can grow the output
+	// substantially (quadratically in the worst case) on large graphs.
+	ComputeClosures bool
+
+	// Anonymize replaces component names, path segments and symbols with stable
+	// pseudonyms so graphs can be shared without leaking internal naming
+	Anonymize bool
+
+	// TreeRoot chooses the top of the directory tree: "" or "scanned" for the
+	// scanned directory, "source" for the detected source root (baseUrl or src),
+	// or a subpath relative to the scanned directory. NodesMap is never pruned.
+	TreeRoot string
+
+	// ProjectName overrides the root node's display name. By default the root
+	// is named after the nearest package.json "name", or else the directory.
+	ProjectName string
+
+	// AliasOverride is merged over the alias configuration detected from the
+	// project's config files (see MergeAliasConfig): its aliases replace
+	// detected ones with the same specifier, its BaseDirs and RootDirs are
+	// searched before the detected ones, and its BaseURL wins when set. Its
+	// alias targets are relative to its own BaseURL, or else the project root.
+	AliasOverride *AliasConfig
+
+	// StateSignatures are extra content signatures (e.g. "createMachine(" for
+	// XState or "proxy(" for Valtio) that mark a file as state management
+	StateSignatures []string
+
+	// FlattenBarrels links imports of named symbols from barrel files directly
+	// to the files that define them, following renamed re-exports
+	FlattenBarrels bool
+
+	// PathBase selects what node IDs and paths are relative to: "" or "root"
+	// for the scanned directory, "cwd" for the working directory, or "absolute"
+	PathBase string
+
+	// Logger receives scan diagnostics. It defaults to slog.Default(), which
+	// writes through the standard log package. Warnings are always collected
+	// in Project.ScanWarnings as well.
+	Logger *slog.Logger
+
+	// Quiet discards all diagnostics unless a Logger is provided
+	Quiet bool
+
+	// CaseInsensitive falls back to case-insensitive file matching when an
+	// import does not resolve literally, mirroring macOS and Windows filesystems
+	CaseInsensitive bool
+
+	// CollapseExternals adds a single "externals" node that every file using a
+	// third-party package imports, with per-package counts in its Packages.
+	// The node is added last, so it is left out of the stats, the analyses,
+	// the filters and the directory tree.
+	CollapseExternals bool
+
+	// MinFanIn and MinFanOut hide nodes imported by, or importing, fewer
+	// files than the thresholds (see FilterByFanInOut). Scan without them to
+	// get the full graph.
+	MinFanIn  int
+	MinFanOut int
+
+	// TransitiveReduction drops import edges implied by longer paths (see
+	// TransitiveReduction) for a sparser graph with the same reachability
+	TransitiveReduction bool
+
+	// Platform selects React Native platform variants when resolving imports:
+	// "ios" or "android" prefer Button.ios.tsx, then Button.native.tsx, then
+	// Button.tsx; "native" and "web" prefer their own variant. Without it,
+	// plain files and directory indexes win and variants are used only when
+	// neither exists.
+	Platform string
+
+	// IncludeGitInfo annotates nodes with their last commit date and primary
+	// author. It runs git blame for every file and is slow on large projects.
+	IncludeGitInfo bool
+
+	// IncludeFileHash stores a SHA-256 of each file on its node and an
+	// aggregate over all of them in ProjectStats, so pipelines can tell
+	// whether anything changed since a previous scan
+	IncludeFileHash bool
+
+	// JSONKeys selects the key style of JSON output: "" or "camel" for the
+	// default camelCase keys, or "snake" for snake_case (e.g. imported_by)
+	JSONKeys string
+
+	// NoSave stops GetProjectJSONWithOptions from saving a timestamped copy
+	// under ~/.local/reactviz, so nothing is written to disk
+	NoSave bool
+
+	// UseAST extracts imports and exports with a JS/TS tokenizer instead of the
+	// default regexes. It ignores comments and string contents and picks up
+	// side-effect imports, at some extra parsing cost. Files the tokenizer
+	// cannot lex fall back to the regexes. Component classification is
+	// heuristic in both modes.
+	UseAST bool
+
+	// ParseTimeout bounds the time spent parsing each source file and the
+	// project's config files, so degenerate input cannot stall the scan. The
+	// deadline is checked between parsing passes, between config files and
+	// between the regex passes over JS configs. A file that times out is
+	// skipped with a warning; timed-out config falls back to the defaults.
+	// Zero means no limit.
+	ParseTimeout time.Duration
+
+	// FS, when set, is read instead of the disk. Its paths are relative to the
+	// scanned root; files outside it (e.g. alias targets above the root) are
+	// treated as missing and symlinks are not followed.
+	FS fs.FS
+}
+
+// DefaultScanOptions returns the options used by ScanProject, with
+// CaseInsensitive enabled on platforms whose filesystems ignore case
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		CaseInsensitive: runtime.GOOS == "darwin" || runtime.GOOS == "windows",
+	}
+}
+
+// logger returns the logger scan diagnostics are sent to
+func (o ScanOptions) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	if o.Quiet {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return slog.Default()
+}
+
+// skipsDir reports whether a directory with the given name should be skipped
+func (o ScanOptions) skipsDir(name string) bool {
+	for _, skip := range o.SkipDirs {
+		if name == skip {
+			return true
+		}
+	}
+	return !o.ReplaceSkipDirs && isSkippedDir(name)
+}
+
+// skipsPath reports whether a file relative to the scanned root lies in a
+// directory the walk would skip
+func (o ScanOptions) skipsPath(relPath string) bool {
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/")
+	for _, dir := range dirs {
+		if dir != "." && dir != ".." && o.skipsDir(dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// propsParamRegex matches a component whose first parameter is annotated with
+// a props type, e.g. function Card({ title }: CardProps) or
+// const Card = (props: CardProps) =>
+var propsParamRegex = regexp.MustCompile(`(?:function\s+[A-Z][\w$]*|const\s+[A-Z][\w$]*\s*=\s*(?:async\s*)?)\s*(?:<[^>(]*>)?\s*\(\s*(?:\{[^)]*\}|[\w$]+)\s*:\s*([A-Z][\w$]*)`)
+
+// propsGenericRegex matches a component typed through React.FC<CardProps>
+var propsGenericRegex = regexp.MustCompile(`const\s+[A-Z][\w$]*\s*:\s*(?:React\.)?(?:FC|FunctionComponent|VFC)\s*<\s*([A-Z][\w$]*)`)
+
+// propsMemberRegex matches the name at the start of a type member such as
+// "title: string", "onClick?(): void" or "readonly id: number"
+var propsMemberRegex = regexp.MustCompile(`^\s*(?:readonly\s+)?([A-Za-z_$][\w$]*)\s*\??\s*[:(]`)
+
+// findProps returns the props type a component is declared with and the
+// fields of its interface or type literal, when both are in the same file.
+// Comments and strings are ignored.
+func findProps(content string) (string, []string) {
+	code := stripNonCode(content)
+	for _, re := range []*regexp.Regexp{propsParamRegex, propsGenericRegex} {
+		for _, match := range re.FindAllStringSubmatch(code, -1) {
+			if body, ok := propsTypeBody(code, match[1]); ok {
+				return match[1], propsMembers(body)
+			}
+		}
+	}
+	return "", nil
+}
+
+// propsTypeBody returns the text between the braces of the interface or
+// object type alias declaring name
+func propsTypeBody(code, name string) (string, bool) {
+	declRegex := regexp.MustCompile(`\b(?:interface\s+` + regexp.QuoteMeta(name) + `\b[^{=;]*|type\s+` + regexp.QuoteMeta(name) + `\s*=\s*)\{`)
+	loc := declRegex.FindStringIndex(code)
+	if loc == nil {
+		return "", false
+	}
+
+	depth := 0
+	for i := loc[1] - 1; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return code[loc[1]:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// propsMembers returns the member names of a type literal body, skipping
+// anything nested in braces, brackets or parentheses
+func propsMembers(body string) []string {
+	members := []string{}
+	depth := 0
+	start := 0
+	flush := func(end int) {
+		if match := propsMemberRegex.FindStringSubmatch(body[start:end]); match != nil {
+			members = append(members, match[1])
+		}
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '{', '(', '[', '<':
+			depth++
+		case '}', ')', ']':
+			depth--
+		case '>':
+			if i == 0 || body[i-1] != '=' {
+				depth--
+			}
+		case ';', ',', '\n':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(body))
+	return members
+}
+
+// tokenProps is the tokenizer-based counterpart of findProps. It follows the
+// same declarations but reads members from tokens, so it is not confused by
+// nested types spanning several lines.
+func tokenProps(content string) (string, []string, error) {
+	toks, err := tokenizeJS(content)
+	if err != nil {
+		return "", nil, err
+	}
+
+	at := func(i int, kind jsTokenKind, text string) bool {
+		return i >= 0 && i < len(toks) && toks[i].kind == kind && (text == "" || toks[i].text == text)
+	}
+	capitalized := func(i int) bool {
+		return at(i, jsIdent, "") && toks[i].text[0] >= 'A' && toks[i].text[0] <= 'Z'
+	}
+	// closing returns the index of the token closing the bracket opened at i
+	closing := func(i int, open, close string) int {
+		depth := 0
+		for j := i; j < len(toks); j++ {
+			if at(j, jsPunct, open) {
+				depth++
+			} else if at(j, jsPunct, close) {
+				depth--
+				if depth == 0 {
+					return j
+				}
+			}
+		}
+		return len(toks)
+	}
+
+	// startsMember reports whether token i begins a type member: it follows the
+	// opening brace, a separator, a readonly modifier, or a line break after a
+	// complete type
+	startsMember := func(i int) bool {
+		prev := toks[i-1]
+		if prev.kind == jsPunct && (prev.text == "{" || prev.text == ";" || prev.text == ",") {
+			return true
+		}
+		if at(i-1, jsIdent, "readonly") {
+			return true
+		}
+		if !strings.Contains(content[prev.end:toks[i].pos], "\n") {
+			return false
+		}
+		return prev.kind != jsPunct || !strings.Contains(":|&=<>", prev.text)
+	}
+
+	// Props types referenced by component signatures, in source order
+	candidates := []string{}
+	for i := range toks {
+		switch {
+		case at(i, jsIdent, "const") && capitalized(i+1) && at(i+2, jsPunct, ":"):
+			j := i + 3
+			if at(j, jsIdent, "React") && at(j+1, jsPunct, ".") {
+				j += 2
+			}
+			if at(j, jsIdent, "") && (toks[j].text == "FC" || toks[j].text == "FunctionComponent" || toks[j].text == "VFC") &&
+				at(j+1, jsPunct, "<") && capitalized(j+2) {
+				candidates = append(candidates, toks[j+2].text)
+			}
+		case (at(i, jsIdent, "function") && capitalized(i+1)) ||
+			(at(i, jsIdent, "const") && capitalized(i+1) && at(i+2, jsPunct, "=")):
+			j := i + 2
+			for j < len(toks) && !at(j, jsPunct, "(") && !at(j, jsPunct, ";") && !at(j, jsPunct, "{") {
+				j++
+			}
+			if !at(j, jsPunct, "(") {
+				continue
+			}
+			// The annotation follows the first parameter, which may destructure
+			k := j + 1
+			if at(k, jsPunct, "{") {
+				k = closing(k, "{", "}") + 1
+			} else if at(k, jsIdent, "") {
+				k++
+			}
+			if at(k, jsPunct, ":") && capitalized(k+1) {
+				candidates = append(candidates, toks[k+1].text)
+			}
+		}
+	}
+
+	for _, name := range candidates {
+		for i := range toks {
+			var open int
+			switch {
+			case at(i, jsIdent, "interface") && at(i+1, jsIdent, name):
+				open = i + 2
+				for open < len(toks) && !at(open, jsPunct, "{") {
+					open++
+				}
+			case at(i, jsIdent, "type") && at(i+1, jsIdent, name) && at(i+2, jsPunct, "=") && at(i+3, jsPunct, "{"):
+				open = i + 3
+			default:
+				continue
+			}
+
+			members := []string{}
+			end := closing(open, "{", "}")
+			depth := 0
+			for j := open + 1; j < end; j++ {
+				switch {
+				case at(j, jsPunct, "{") || at(j, jsPunct, "(") || at(j, jsPunct, "["):
+					depth++
+				case at(j, jsPunct, "}") || at(j, jsPunct, ")") || at(j, jsPunct, "]"):
+					depth--
+				case depth == 0 && (at(j, jsIdent, "") || at(j, jsString, "")) && startsMember(j) &&
+					(at(j+1, jsPunct, ":") || at(j+1, jsPunct, "?") || at(j+1, jsPunct, "(")):
+					members = append(members, toks[j].text)
+				}
+			}
+			return name, members, nil
+		}
+	}
+	return "", nil, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypedPropsInterface(t *testing.T) {
+	files := map[string]string{
+		"src/Button.tsx": `import React from 'react'
+
+// ButtonProps documents the button
+export interface ButtonProps extends React.HTMLAttributes<HTMLButtonElement> {
+  label: string
+  variant?: 'primary' | 'ghost' // the look
+  readonly size: number
+  onClick(event: MouseEvent): void
+  style: { color: string; margin: number }
+}
+
+export function Button({ label, variant }: ButtonProps) {
+  return <button>{label}</button>
+}
+`,
+		"src/Card.tsx": `type CardProps = { title: string; footer?: React.ReactNode }
+
+export const Card: React.FC<CardProps> = ({ title }) => <div>{title}</div>
+`,
+	}
+
+	for _, useAST := range []bool{false, true} {
+		project := scanFiles(t, files, ScanOptions{UseAST: useAST})
+		tests := []struct {
+			id, propsType string
+			props         []string
+		}{
+			{"src/Button.tsx", "ButtonProps", []string{"label", "variant", "size", "onClick", "style"}},
+			{"src/Card.tsx", "CardProps", []string{"title", "footer"}},
+		}
+		for _, tt := range tests {
+			node := nodeAt(t, project, tt.id)
+			if node.PropsType != tt.propsType || !reflect.DeepEqual(node.Props, tt.props) {
+				t.Errorf("UseAST=%v: %s props = %s %v, want %s %v", useAST, tt.id, node.PropsType, node.Props, tt.propsType, tt.props)
+			}
+		}
+	}
+}
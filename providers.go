@@ -0,0 +1,78 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// renderRootRegex finds where an entry or root component starts rendering
+var renderRootRegex = regexp.MustCompile(`\brender\(|\breturn\s*\(?\s*<`)
+
+// jsxOpenTagRegex matches opening JSX tags of components, e.g. <ThemeProvider or <Ctx.Provider
+var jsxOpenTagRegex = regexp.MustCompile(`<([A-Z][\w$]*(?:\.[\w$]+)*)[\s/>]`)
+
+// maxProviderDepth bounds how many components are followed below the entry
+const maxProviderDepth = 5
+
+// isProviderTag reports whether a JSX tag name looks like a context provider
+func isProviderTag(name string) bool {
+	return strings.HasSuffix(name, "Provider")
+}
+
+// detectProviderStack returns the providers wrapping the app, outermost
+// first, for the first entry point that renders any. It is a heuristic: the
+// rendered JSX is read top to bottom and the first project component that is
+// not a provider is followed into its own file.
+func detectProviderStack(project Project, src sourceFS, rootDir string, entries []string) []string {
+	for _, entry := range entries {
+		if stack := providersFrom(project, src, rootDir, entry, map[string]bool{}, 0); len(stack) > 0 {
+			return stack
+		}
+	}
+	return nil
+}
+
+// providersFrom collects the providers rendered by the node at id and the
+// component it wraps
+func providersFrom(project Project, src sourceFS, rootDir, id string, visited map[string]bool, depth int) []string {
+	node, exists := project.NodesMap[id]
+	if !exists || visited[id] || depth > maxProviderDepth {
+		return nil
+	}
+	visited[id] = true
+
+	content, err := src.ReadFile(filepath.Join(rootDir, id))
+	if err != nil {
+		return nil
+	}
+	text := string(content)
+	loc := renderRootRegex.FindStringIndex(text)
+	if loc == nil {
+		return nil
+	}
+
+	stack := []string{}
+	for _, match := range jsxOpenTagRegex.FindAllStringSubmatch(text[loc[0]:], -1) {
+		tag := match[1]
+		if isProviderTag(tag) {
+			stack = append(stack, tag)
+			continue
+		}
+		// The first project component inside the providers is the app itself
+		if target, ok := importedComponent(node, tag); ok {
+			return append(stack, providersFrom(project, src, rootDir, target, visited, depth+1)...)
+		}
+	}
+	return stack
+}
+
+// importedComponent returns the file a JSX tag was imported from
+func importedComponent(node ComponentNode, tag string) (string, bool) {
+	for _, edge := range node.ImportEdges {
+		if containsString(edge.localBindings(), tag) {
+			return edge.Target, true
+		}
+	}
+	return "", false
+}
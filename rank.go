@@ -0,0 +1,82 @@
+package main
+
+import "sort"
+
+// Rank settings: the usual PageRank damping factor and a fixed number of
+// iterations so scores are identical across runs
+const (
+	rankDamping    = 0.85
+	rankIterations = 50
+	topRankedLimit = 10
+)
+
+// computeRanks scores every node with PageRank over the import graph, so a
+// node ranks high when it is imported by other highly ranked nodes. Scores sum
+// to 1; nodes importing nothing share their rank evenly with every node.
+func computeRanks(project *Project) {
+	ids := sortedNodeIDs(*project)
+	n := len(ids)
+	if n == 0 {
+		return
+	}
+
+	// Deduplicated outgoing edges between scanned nodes
+	index := make(map[string]int, n)
+	for i, id := range ids {
+		index[id] = i
+	}
+	out := make([][]int, n)
+	for i, id := range ids {
+		seen := make(map[int]bool)
+		for _, target := range project.NodesMap[id].Imports {
+			if j, exists := index[target]; exists && j != i && !seen[j] {
+				seen[j] = true
+				out[i] = append(out[i], j)
+			}
+		}
+	}
+
+	ranks := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = 1 / float64(n)
+	}
+	for iteration := 0; iteration < rankIterations; iteration++ {
+		dangling := 0.0
+		for i := range ids {
+			if len(out[i]) == 0 {
+				dangling += ranks[i]
+			}
+		}
+
+		next := make([]float64, n)
+		base := (1-rankDamping)/float64(n) + rankDamping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, targets := range out {
+			share := rankDamping * ranks[i] / float64(len(targets))
+			for _, j := range targets {
+				next[j] += share
+			}
+		}
+		ranks = next
+	}
+
+	for i, id := range ids {
+		node := project.NodesMap[id]
+		node.Rank = ranks[i]
+		project.NodesMap[id] = node
+	}
+}
+
+// topRanked returns up to limit node IDs with the highest rank, ties broken by ID
+func topRanked(project Project, limit int) []string {
+	ids := sortedNodeIDs(project)
+	sort.SliceStable(ids, func(i, j int) bool {
+		return project.NodesMap[ids[i]].Rank > project.NodesMap[ids[j]].Rank
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestComputeRanksHubAboveLeaves(t *testing.T) {
+	project := Project{NodesMap: map[string]ComponentNode{
+		"theme.js": {ID: "theme.js"},
+		"Hub.jsx":  {ID: "Hub.jsx", Imports: []string{"theme.js"}},
+	}}
+	for i := 0; i < 4; i++ {
+		leaf := fmt.Sprintf("Leaf%d.jsx", i)
+		// Duplicate and unresolved imports don't add weight
+		project.NodesMap[leaf] = ComponentNode{ID: leaf, Imports: []string{"Hub.jsx", "Hub.jsx", "missing.js"}}
+	}
+
+	computeRanks(&project)
+
+	total := 0.0
+	for _, node := range project.NodesMap {
+		total += node.Rank
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("ranks sum to %v, want 1", total)
+	}
+	hub := project.NodesMap["Hub.jsx"].Rank
+	for i := 0; i < 4; i++ {
+		if leaf := project.NodesMap[fmt.Sprintf("Leaf%d.jsx", i)].Rank; hub <= leaf {
+			t.Errorf("Hub.jsx rank %v not above Leaf%d.jsx rank %v", hub, i, leaf)
+		}
+	}
+	if got := topRanked(project, 2); len(got) != 2 || got[0] != "theme.js" || got[1] != "Hub.jsx" {
+		t.Errorf("topRanked = %v, want [theme.js Hub.jsx]", got)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+)
+
+// ReduxSlice links a Redux Toolkit slice to the store combining it and the
+// files that select from it or use its actions
+type ReduxSlice struct {
+	Name      string   `json:"name"`
+	File      string   `json:"file"`
+	Store     string   `json:"store,omitempty"`
+	Consumers []string `json:"consumers,omitempty"`
+}
+
+// sliceNameRegex matches the name passed to createSlice({ name: 'counter', ... })
+var sliceNameRegex = regexp.MustCompile(`createSlice\(\s*\{[^}]*?\bname\s*:\s*['"]([^'"]+)['"]`)
+
+// configureStoreRegex matches a Redux Toolkit store definition
+var configureStoreRegex = regexp.MustCompile(`\bconfigureStore\s*\(`)
+
+// selectorCallRegex matches a selector hook and the name of its state
+// parameter, e.g. useSelector((state) => ...) or useAppSelector(s => ...)
+var selectorCallRegex = regexp.MustCompile(`\buse\w*Selector\s*\(\s*\(?\s*([A-Za-z_$][\w$]*)`)
+
+// selectorWindow is how far past a selector call its body is searched for
+// state accesses
+const selectorWindow = 200
+
+// findSliceName returns the name of the slice a file creates, if any
+func findSliceName(content string) string {
+	if match := sliceNameRegex.FindStringSubmatch(content); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// findSelectedSlices returns the state keys read by selector hooks, such as
+// "counter" for useSelector((state) => state.counter.value)
+func findSelectedSlices(content string) []string {
+	selected := make(map[string]bool)
+	for _, loc := range selectorCallRegex.FindAllStringSubmatchIndex(content, -1) {
+		param := content[loc[2]:loc[3]]
+		body := content[loc[1]:min(loc[1]+selectorWindow, len(content))]
+		accessRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(param) + `\.([A-Za-z_$][\w$]*)`)
+		for _, access := range accessRegex.FindAllStringSubmatch(body, -1) {
+			selected[access[1]] = true
+		}
+	}
+	return sortedKeys(selected)
+}
+
+// linkReduxSlices relates every slice to the store that reaches it through its
+// imports and to the files that select its state or import it directly
+func linkReduxSlices(project Project) []ReduxSlice {
+	// What each store reaches is computed once, not once per slice
+	stores := []string{}
+	reaches := make(map[string]map[string]bool)
+	for _, id := range sortedNodeIDs(project) {
+		if project.NodesMap[id].ReduxStore {
+			stores = append(stores, id)
+			reaches[id] = reachableFrom(project, []string{id})
+		}
+	}
+
+	slices := []ReduxSlice{}
+	for _, id := range sortedNodeIDs(project) {
+		node := project.NodesMap[id]
+		if node.SliceName == "" {
+			continue
+		}
+		slice := ReduxSlice{Name: node.SliceName, File: id}
+
+		for _, store := range stores {
+			if reaches[store][id] {
+				slice.Store = store
+				break
+			}
+		}
+
+		consumers := make(map[string]bool)
+		for _, importer := range node.ImportedBy {
+			consumers[importer] = true
+		}
+		for otherID, other := range project.NodesMap {
+			if containsString(other.SelectedSlices, node.SliceName) {
+				consumers[otherID] = true
+			}
+		}
+		for consumer := range consumers {
+			if consumer == id || project.NodesMap[consumer].ReduxStore ||
+				project.NodesMap[consumer].Type == "test" {
+				continue
+			}
+			slice.Consumers = append(slice.Consumers, consumer)
+		}
+		sort.Strings(slice.Consumers)
+
+		slices = append(slices, slice)
+	}
+
+	if len(slices) == 0 {
+		return nil
+	}
+	return slices
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// registryExportRegex matches a default-exported object literal, e.g. export default { Button, Card }
+var registryExportRegex = regexp.MustCompile(`export\s+default\s+\{([^}]*)\}`)
+
+// identifierRegex matches a plain JavaScript identifier
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// findRegistryMembers detects a component registry (a default-exported object of
+// imported components) and maps each member name to the file it was imported from
+func findRegistryMembers(content string, edges []ImportEdge) map[string]string {
+	match := registryExportRegex.FindStringSubmatch(content)
+	if match == nil {
+		return nil
+	}
+
+	// Map local binding names to the files that provide them
+	bindings := make(map[string]string)
+	for _, edge := range edges {
+		for _, local := range edge.localBindings() {
+			bindings[local] = edge.Target
+		}
+	}
+
+	members := make(map[string]string)
+	for _, entry := range strings.Split(match[1], ",") {
+		key, value, found := strings.Cut(entry, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found {
+			value = key
+		}
+
+		if !identifierRegex.MatchString(key) || !identifierRegex.MatchString(value) {
+			continue
+		}
+		if target, ok := bindings[value]; ok {
+			members[key] = target
+		}
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+	return members
+}
+
+// recordMemberAccess notes which members of a default or namespace import are
+// accessed (e.g. DS.Button or <DS.Button />) so registry usage can be attributed
+func recordMemberAccess(content string, edges []ImportEdge) {
+	for i, edge := range edges {
+		for _, local := range []string{edge.Default, edge.Namespace} {
+			if local == "" {
+				continue
+			}
+
+			accessRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(local) + `\.([A-Z][\w$]*)`)
+			seen := make(map[string]bool)
+			for _, access := range accessRegex.FindAllStringSubmatch(content, -1) {
+				if !seen[access[1]] {
+					seen[access[1]] = true
+					edges[i].Members = append(edges[i].Members, access[1])
+				}
+			}
+		}
+	}
+}
+
+// linkRegistryMembers adds registry edges from consumers to the components they
+// reach through a registry object, and from each registry to its members
+func linkRegistryMembers(project *Project) {
+	for id, node := range project.NodesMap {
+		var added []ImportEdge
+
+		for _, edge := range node.ImportEdges {
+			registry, exists := project.NodesMap[edge.Target]
+			if !exists || len(registry.RegistryMembers) == 0 {
+				continue
+			}
+			for _, member := range edge.Members {
+				if target, ok := registry.RegistryMembers[member]; ok {
+					added = append(added, ImportEdge{Target: target, Kind: "registry", Named: []string{member}})
+				}
+			}
+		}
+
+		if len(added) > 0 {
+			node.ImportEdges = append(node.ImportEdges, added...)
+			node.Imports = edgeTargets(node.ImportEdges)
+			project.NodesMap[id] = node
+		}
+	}
+}
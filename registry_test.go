@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestComponentRegistry(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/ds/index.js": `import Button from './Button'
+import { Card } from './Card'
+import Modal from './Modal'
+
+export default { Button, Card, Dialog: Modal }
+`,
+		"src/ds/Button.jsx": "export default function Button() { return <button /> }\n",
+		"src/ds/Card.jsx":   "export function Card() { return <div /> }\n",
+		"src/ds/Modal.jsx":  "export default function Modal() { return <dialog /> }\n",
+		"src/App.jsx": `import DS from './ds'
+
+export default function App() {
+  return <DS.Button><DS.Dialog /></DS.Button>
+}
+`,
+	}, ScanOptions{})
+
+	want := map[string]string{
+		"Button": filepath.Join("src", "ds", "Button.jsx"),
+		"Card":   filepath.Join("src", "ds", "Card.jsx"),
+		"Dialog": filepath.Join("src", "ds", "Modal.jsx"),
+	}
+	if got := nodeAt(t, project, "src/ds/index.js").RegistryMembers; !reflect.DeepEqual(got, want) {
+		t.Errorf("registry members = %v, want %v", got, want)
+	}
+
+	for _, target := range []string{"src/ds/Button.jsx", "src/ds/Modal.jsx"} {
+		edge, ok := edgeTo(t, project, "src/App.jsx", target)
+		if !ok || edge.Kind != "registry" {
+			t.Errorf("edge to %s = %+v, %v; want a registry edge", target, edge, ok)
+		}
+	}
+	if _, ok := edgeTo(t, project, "src/App.jsx", "src/ds/Card.jsx"); ok {
+		t.Error("App.jsx is linked to Card.jsx, which it never uses")
+	}
+}
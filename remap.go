@@ -0,0 +1,350 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// projectRemapper rewrites the identifiers stored throughout a project. Every
+// field holding a node ID or file path is rewritten with path; imported and
+// exported symbol names, as well as the names in route patterns, import globs
+// and environment conditions, are rewritten with symbol when it is set.
+type projectRemapper struct {
+	path   func(string) string
+	symbol func(string) string
+}
+
+// apply rewrites the project in place. Slices and maps are copied rather than
+// modified because nodes in the tree share them with nodes in NodesMap.
+func (r projectRemapper) apply(project *Project) {
+	project.Files = r.paths(project.Files)
+	if project.ScanWarnings != nil {
+		warnings := make([]string, len(project.ScanWarnings))
+		for i, warning := range project.ScanWarnings {
+			warnings[i] = r.text(warning)
+		}
+		project.ScanWarnings = warnings
+	}
+	project.EntryPoints = r.paths(project.EntryPoints)
+	project.Unreachable = r.paths(project.Unreachable)
+	project.Untested = r.paths(project.Untested)
+	project.MostComplex = r.paths(project.MostComplex)
+	project.TopRanked = r.paths(project.TopRanked)
+	if project.UsageRanking != nil {
+		ranking := make([]ComponentUsage, len(project.UsageRanking))
+		for i, usage := range project.UsageRanking {
+			usage.ID = r.path(usage.ID)
+			ranking[i] = usage
+		}
+		project.UsageRanking = ranking
+	}
+	project.ProviderStack = r.syms(project.ProviderStack)
+	if project.ReduxSlices != nil {
+		slices := make([]ReduxSlice, len(project.ReduxSlices))
+		for i, slice := range project.ReduxSlices {
+			slice.Name = r.sym(slice.Name)
+			slice.File = r.path(slice.File)
+			if slice.Store != "" {
+				slice.Store = r.path(slice.Store)
+			}
+			slice.Consumers = r.paths(slice.Consumers)
+			slices[i] = slice
+		}
+		project.ReduxSlices = slices
+	}
+	if project.Chunks != nil {
+		chunks := make([]Chunk, len(project.Chunks))
+		for i, chunk := range project.Chunks {
+			chunks[i] = Chunk{Entry: r.path(chunk.Entry), Members: r.paths(chunk.Members)}
+		}
+		project.Chunks = chunks
+	}
+	if project.BarrelChains != nil {
+		chains := make([]BarrelChain, len(project.BarrelChains))
+		for i, chain := range project.BarrelChains {
+			chains[i] = BarrelChain{Leaf: r.path(chain.Leaf), Chain: r.paths(chain.Chain), Length: chain.Length}
+		}
+		project.BarrelChains = chains
+	}
+	if project.Clusters != nil {
+		clusters := make([][]string, len(project.Clusters))
+		for i, cluster := range project.Clusters {
+			clusters[i] = r.paths(cluster)
+		}
+		project.Clusters = clusters
+	}
+
+	if project.Cycles != nil {
+		cycles := make([]Cycle, len(project.Cycles))
+		for i, cycle := range project.Cycles {
+			cycle.Nodes = r.paths(cycle.Nodes)
+			cycle.Dir = r.path(cycle.Dir)
+			cycles[i] = cycle
+		}
+		project.Cycles = cycles
+	}
+
+	if project.ResolutionWarnings != nil {
+		warnings := make([]ResolutionWarning, len(project.ResolutionWarnings))
+		for i, warning := range project.ResolutionWarnings {
+			remapped := ResolutionWarning{
+				Name:      r.sym(warning.Name),
+				Copies:    r.paths(warning.Copies),
+				Packages:  make(map[string]string, len(warning.Packages)),
+				Importers: make(map[string][]string, len(warning.Importers)),
+			}
+			for id, pkg := range warning.Packages {
+				if pkg != "" {
+					pkg = r.path(pkg)
+				}
+				remapped.Packages[r.path(id)] = pkg
+			}
+			for id, importers := range warning.Importers {
+				remapped.Importers[r.path(id)] = r.paths(importers)
+			}
+			warnings[i] = remapped
+		}
+		project.ResolutionWarnings = warnings
+	}
+	project.RouteTree = r.routes(project.RouteTree)
+	if project.TreeRoot != "" {
+		project.TreeRoot = r.path(project.TreeRoot)
+	}
+	if project.InferredAliases != nil {
+		inferred := make(map[string]string, len(project.InferredAliases))
+		for alias, dir := range project.InferredAliases {
+			inferred[alias] = r.path(dir)
+		}
+		project.InferredAliases = inferred
+	}
+
+	nodesMap := make(map[string]ComponentNode, len(project.NodesMap))
+	for id, node := range project.NodesMap {
+		nodesMap[r.path(id)] = r.node(node)
+	}
+	project.NodesMap = nodesMap
+
+	children := make([]ComponentNode, len(project.Root.Children))
+	for i, child := range project.Root.Children {
+		children[i] = r.node(child)
+	}
+	if project.Root.Children != nil {
+		project.Root.Children = children
+	}
+}
+
+// node returns a rewritten copy of a node and its children
+func (r projectRemapper) node(node ComponentNode) ComponentNode {
+	node.ID = r.path(node.ID)
+	node.Path = r.path(node.Path)
+	node.Imports = r.paths(node.Imports)
+	node.ImportedBy = r.paths(node.ImportedBy)
+	node.Exports = r.syms(node.Exports)
+	node.PropsType = r.sym(node.PropsType)
+	node.Props = r.syms(node.Props)
+	node.SliceName = r.sym(node.SliceName)
+	node.SelectedSlices = r.syms(node.SelectedSlices)
+	node.TransitiveDeps = r.paths(node.TransitiveDeps)
+	node.TransitiveDependents = r.paths(node.TransitiveDependents)
+	node.Collapsed = r.paths(node.Collapsed)
+	node.DataRoutes = r.routes(node.DataRoutes)
+	if node.Routes != nil {
+		routes := make([]RouteInfo, len(node.Routes))
+		for i, route := range node.Routes {
+			routes[i] = RouteInfo{Pattern: r.pattern(route.Pattern), Params: r.syms(route.Params)}
+		}
+		node.Routes = routes
+	}
+
+	if node.ImportEdges != nil {
+		edges := make([]ImportEdge, len(node.ImportEdges))
+		for i, edge := range node.ImportEdges {
+			edge.Target = r.path(edge.Target)
+			edge.Default = r.sym(edge.Default)
+			edge.Namespace = r.sym(edge.Namespace)
+			edge.Named = r.syms(edge.Named)
+			edge.Members = r.syms(edge.Members)
+			edge.Pattern = r.pattern(edge.Pattern)
+			edge.Route = r.pattern(edge.Route)
+			edge.Condition = r.condition(edge.Condition)
+			if edge.Via != "" {
+				edge.Via = r.path(edge.Via)
+			}
+			if edge.Reexports != nil {
+				reexports := make([]ExportAlias, len(edge.Reexports))
+				for j, alias := range edge.Reexports {
+					reexports[j] = ExportAlias{Local: r.sym(alias.Local), Exported: r.sym(alias.Exported)}
+				}
+				edge.Reexports = reexports
+			}
+			if edge.LocalNames != nil {
+				localNames := make(map[string]string, len(edge.LocalNames))
+				for imported, local := range edge.LocalNames {
+					localNames[r.sym(imported)] = r.sym(local)
+				}
+				edge.LocalNames = localNames
+			}
+			if edge.Renamed != nil {
+				renamed := make(map[string]string, len(edge.Renamed))
+				for original, imported := range edge.Renamed {
+					renamed[r.sym(original)] = r.sym(imported)
+				}
+				edge.Renamed = renamed
+			}
+			edges[i] = edge
+		}
+		node.ImportEdges = edges
+	}
+
+	if node.ImportedByDetails != nil {
+		refs := make([]ImporterRef, len(node.ImportedByDetails))
+		for i, ref := range node.ImportedByDetails {
+			ref.Importer = r.path(ref.Importer)
+			ref.Named = r.syms(ref.Named)
+			refs[i] = ref
+		}
+		node.ImportedByDetails = refs
+	}
+
+	if node.RegistryMembers != nil {
+		members := make(map[string]string, len(node.RegistryMembers))
+		for member, target := range node.RegistryMembers {
+			members[r.sym(member)] = r.path(target)
+		}
+		node.RegistryMembers = members
+	}
+
+	if node.Children != nil {
+		children := make([]ComponentNode, len(node.Children))
+		for i, child := range node.Children {
+			children[i] = r.node(child)
+		}
+		node.Children = children
+	}
+
+	return node
+}
+
+// routes returns a rewritten copy of a route tree
+func (r projectRemapper) routes(routes []RouteNode) []RouteNode {
+	if routes == nil {
+		return nil
+	}
+	mapped := make([]RouteNode, len(routes))
+	for i, route := range routes {
+		route.Path = r.pattern(route.Path)
+		route.File = r.path(route.File)
+		if route.Component != "" {
+			route.Component = r.path(route.Component)
+		}
+		route.Children = r.routes(route.Children)
+		mapped[i] = route
+	}
+	return mapped
+}
+
+// messagePathRegex matches what a message such as a scan warning may name:
+// quoted aliases and targets, paths with a separator, and file names
+var messagePathRegex = regexp.MustCompile(`"[^"]*"|[\w@~.$-]*[/\\][\w@~.$/\\-]*|[\w@~.$-]+\.[A-Za-z]\w*`)
+
+// text rewrites the paths a message mentions with path, and the quoted names
+// in it, such as aliases, with symbol
+func (r projectRemapper) text(message string) string {
+	return messagePathRegex.ReplaceAllStringFunc(message, func(match string) string {
+		if strings.HasPrefix(match, `"`) {
+			return strconv.Quote(r.sym(match[1 : len(match)-1]))
+		}
+		trimmed := strings.TrimRight(match, ".")
+		return r.path(trimmed) + match[len(trimmed):]
+	})
+}
+
+// patternNameRegex matches a name in a route pattern or import glob, such as
+// a path segment, a route parameter or a file extension
+var patternNameRegex = regexp.MustCompile(`[A-Za-z_$][\w$-]*`)
+
+// pattern rewrites the names in a URL route pattern or an import glob with
+// symbol, keeping separators, wildcards, parameter markers and the extension
+// of a wildcard file name such as *.json
+func (r projectRemapper) pattern(value string) string {
+	if r.symbol == nil || value == "" {
+		return value
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range patternNameRegex.FindAllStringIndex(value, -1) {
+		b.WriteString(value[last:loc[0]])
+		if name := value[loc[0]:loc[1]]; strings.HasSuffix(value[:loc[0]], "*.") {
+			b.WriteString(name)
+		} else {
+			b.WriteString(r.symbol(name))
+		}
+		last = loc[1]
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// conditionTokenRegex matches the string literals and identifiers of an
+// environment condition
+var conditionTokenRegex = regexp.MustCompile(`'[^']*'|"[^"]*"|[A-Za-z_$][\w$]*`)
+
+// conditionKeywords are the tokens of environment conditions that name no
+// part of the project and are kept as they are
+var conditionKeywords = map[string]bool{
+	"process": true, "env": true, "import": true, "meta": true, "typeof": true,
+	"NODE_ENV": true, "MODE": true, "DEV": true, "PROD": true, "SSR": true, "__DEV__": true,
+	"undefined": true, "null": true, "true": true, "false": true,
+	"development": true, "production": true, "test": true,
+}
+
+// condition rewrites the identifiers and strings of an environment condition
+// with symbol, keeping the standard environment variables and modes
+func (r projectRemapper) condition(value string) string {
+	if r.symbol == nil || value == "" {
+		return value
+	}
+	return conditionTokenRegex.ReplaceAllStringFunc(value, func(token string) string {
+		name, quote := token, ""
+		if token[0] == '\'' || token[0] == '"' {
+			name, quote = token[1:len(token)-1], token[:1]
+		}
+		if name == "" || conditionKeywords[name] {
+			return token
+		}
+		return quote + r.symbol(name) + quote
+	})
+}
+
+// paths returns a rewritten copy of a list of paths
+func (r projectRemapper) paths(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	mapped := make([]string, len(values))
+	for i, value := range values {
+		mapped[i] = r.path(value)
+	}
+	return mapped
+}
+
+// sym rewrites a single symbol name, leaving empty names alone
+func (r projectRemapper) sym(value string) string {
+	if r.symbol == nil || value == "" {
+		return value
+	}
+	return r.symbol(value)
+}
+
+// syms returns a rewritten copy of a list of symbol names
+func (r projectRemapper) syms(values []string) []string {
+	if r.symbol == nil || values == nil {
+		return values
+	}
+	mapped := make([]string, len(values))
+	for i, value := range values {
+		mapped[i] = r.symbol(value)
+	}
+	return mapped
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// createElementRegex matches calls whose first argument is a component or a
+// tag name, capturing the optional qualifier, the callee and the argument.
+// Only callees bound to React or Preact count, see elementFactories.
+var createElementRegex = regexp.MustCompile(`(?:^|[^\w$.])(?:([\w$]+)\.)?([\w$]+)\(\s*([A-Z][\w$]*|['"][a-z][\w-]*['"])`)
+
+// reactImportRegex matches import declarations from react or preact,
+// including subpaths such as preact/compat, capturing the import clause
+var reactImportRegex = regexp.MustCompile(`\bimport\s+([^'";]+?)\s+from\s*['"](?:react|preact)(?:/[\w/-]+)?['"]`)
+
+// elementFactories returns the namespaces whose createElement or h member
+// builds elements (React always, plus default and namespace imports of react
+// or preact) and the local names of createElement and h imported by name
+func elementFactories(content string) (namespaces, functions map[string]bool) {
+	namespaces = map[string]bool{"React": true}
+	functions = make(map[string]bool)
+	for _, match := range reactImportRegex.FindAllStringSubmatch(content, -1) {
+		clause := match[1]
+		if open := strings.Index(clause, "{"); open >= 0 {
+			named := strings.TrimSuffix(strings.TrimSpace(clause[open+1:]), "}")
+			for _, spec := range strings.Split(named, ",") {
+				fields := strings.Fields(spec)
+				if len(fields) == 0 || (fields[0] != "createElement" && fields[0] != "h") {
+					continue
+				}
+				local := fields[0]
+				if len(fields) == 3 && fields[1] == "as" {
+					local = fields[2]
+				}
+				functions[local] = true
+			}
+			clause = clause[:open]
+		}
+		for _, binding := range strings.Split(clause, ",") {
+			binding = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(binding), "* as "))
+			if binding != "" && binding != "type" {
+				namespaces[binding] = true
+			}
+		}
+	}
+	return namespaces, functions
+}
+
+// elementCalls returns the first arguments of the React or Preact element
+// creation calls in content, such as React.createElement(Button, ...) or
+// h(Button, ...) with h imported from preact. Calls like
+// document.createElement or an unrelated h helper are ignored.
+func elementCalls(content string) []string {
+	namespaces, functions := elementFactories(content)
+	args := []string{}
+	for _, match := range createElementRegex.FindAllStringSubmatch(content, -1) {
+		qualifier, callee := match[1], match[2]
+		if qualifier != "" && !(namespaces[qualifier] && (callee == "createElement" || callee == "h")) {
+			continue
+		}
+		if qualifier == "" && !functions[callee] {
+			continue
+		}
+		args = append(args, match[3])
+	}
+	return args
+}
+
+// usesCreateElement reports whether content builds elements through
+// createElement or hyperscript calls instead of JSX
+func usesCreateElement(content string) bool {
+	return len(elementCalls(content)) > 0
+}
+
+// recordRenders counts how often each imported binding is rendered, either as
+// a JSX tag or as the first argument of a createElement/h call
+func recordRenders(content string, edges []ImportEdge) {
+	created := make(map[string]int)
+	for _, arg := range elementCalls(content) {
+		if !strings.ContainsAny(arg[:1], `'"`) {
+			created[arg]++
+		}
+	}
+
+	for i, edge := range edges {
+		for _, local := range edge.localBindings() {
+			if local[0] < 'A' || local[0] > 'Z' {
+				continue // only capitalized bindings can be rendered as components
+			}
+			tagRegex := regexp.MustCompile(`<` + regexp.QuoteMeta(local) + `[\s/>]`)
+			edges[i].RenderCount += len(tagRegex.FindAllStringIndex(content, -1)) + created[local]
+		}
+	}
+}
+
+// renderDepth returns the longest path of render edges (imports rendered at
+// least once) starting at any of the entries. Edges back into the current
+// path are skipped, so render cycles terminate.
+func renderDepth(project Project, entries []string) int {
+	depths := make(map[string]int)
+	onPath := make(map[string]bool)
+
+	var depth func(id string) int
+	depth = func(id string) int {
+		if d, ok := depths[id]; ok {
+			return d
+		}
+		onPath[id] = true
+		longest := 0
+		for _, edge := range project.NodesMap[id].ImportEdges {
+			if _, exists := project.NodesMap[edge.Target]; !exists || edge.RenderCount == 0 || onPath[edge.Target] {
+				continue
+			}
+			longest = max(longest, depth(edge.Target)+1)
+		}
+		onPath[id] = false
+		depths[id] = longest
+		return longest
+	}
+
+	deepest := 0
+	for _, entry := range entries {
+		if _, exists := project.NodesMap[entry]; exists {
+			deepest = max(deepest, depth(entry))
+		}
+	}
+	return deepest
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestElementCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"react namespace", "import React from 'react'\nReact.createElement(Button, null)\n", []string{"Button"}},
+		{"global React", "React.createElement('div', null)\n", []string{"'div'"}},
+		{"named createElement", "import { createElement } from 'react'\ncreateElement(Button)\n", []string{"Button"}},
+		{"renamed createElement", "import { createElement as ce } from 'react'\nce(Button)\n", []string{"Button"}},
+		{"preact h", "import { h } from 'preact'\nh(Button, null)\n", []string{"Button"}},
+		{"preact namespace", "import * as Preact from 'preact'\nPreact.h(Button)\n", []string{"Button"}},
+		{"dom createElement", "const el = document.createElement('div')\n", []string{}},
+		{"unimported createElement", "createElement('div')\n", []string{}},
+		{"unrelated h helper", "import { h } from './hyperscript'\nh(Button)\n", []string{}},
+		{"other namespace", "import Vue from 'vue'\nVue.h(Button)\n", []string{}},
+	}
+	for _, tt := range tests {
+		if got := elementCalls(tt.content); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: elementCalls = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDOMCreateElementIsNotAComponent(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/utils/dom.js": "export const mount = () => document.createElement('div')\n",
+		"src/Badge.js":     "import React from 'react'\nexport const badge = () => React.createElement('span', null)\n",
+	}, ScanOptions{})
+
+	if got := nodeAt(t, project, "src/utils/dom.js").Type; got == "component" {
+		t.Errorf("dom.js type = %q, want a non-component", got)
+	}
+	if got := nodeAt(t, project, "src/Badge.js").Type; got != "component" {
+		t.Errorf("Badge.js type = %q, want component", got)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RouteInfo describes a route pattern and the dynamic parameters it declares
+type RouteInfo struct {
+	Pattern string   `json:"pattern"` // e.g. /users/:id
+	Params  []string `json:"params,omitempty"`
+}
+
+// reactRouterPathRegex matches route paths in <Route path="..."> elements and route config objects
+var reactRouterPathRegex = regexp.MustCompile(`(?:<Route\b[^>]*\bpath=|\bpath\s*:\s*)['"]([^'"]*)['"]`)
+
+// colonParamRegex matches a React Router dynamic segment such as :id or :id?
+var colonParamRegex = regexp.MustCompile(`:([A-Za-z_][\w]*)`)
+
+// routeObjectPathRegex matches the path property of a route config object
+var routeObjectPathRegex = regexp.MustCompile(`\bpath\s*:\s*['"]([^'"]*)['"]`)
+
+// routeAt returns the path of the route config object, such as
+// { path: '/x', component: lazy(() => import('./X')) }, enclosing offset.
+// Braces are matched in code, where strings and comments are blanked out, and
+// the path is read from content at the same offsets.
+func routeAt(code, content string, offset int) string {
+	// Find the innermost object literal around offset
+	start, depth := -1, 0
+	for i := offset - 1; i >= 0; i-- {
+		switch code[i] {
+		case '}':
+			depth++
+		case '{':
+			if depth == 0 {
+				start = i
+			} else {
+				depth--
+			}
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	end, depth := len(code), 0
+	for i := start; i < len(code); i++ {
+		if code[i] == '{' {
+			depth++
+		} else if code[i] == '}' {
+			depth--
+			if depth == 0 {
+				end = i
+				break
+			}
+		}
+	}
+
+	// Only a path at the object's own level names its route
+	for _, loc := range routeObjectPathRegex.FindAllStringSubmatchIndex(content[start:end], -1) {
+		nested := 0
+		for _, c := range code[start+1 : start+loc[0]] {
+			switch c {
+			case '{', '[', '(':
+				nested++
+			case '}', ']', ')':
+				nested--
+			}
+		}
+		if nested == 0 {
+			return content[start+loc[2] : start+loc[3]]
+		}
+	}
+	return ""
+}
+
+// findRouteDefinitions extracts React Router route patterns declared in a file
+func findRouteDefinitions(content string) []RouteInfo {
+	if !strings.Contains(content, "react-router") {
+		return nil
+	}
+
+	routes := []RouteInfo{}
+	seen := make(map[string]bool)
+	for _, match := range reactRouterPathRegex.FindAllStringSubmatch(content, -1) {
+		pattern := match[1]
+		if seen[pattern] {
+			continue
+		}
+		seen[pattern] = true
+
+		route := RouteInfo{Pattern: pattern}
+		for _, param := range colonParamRegex.FindAllStringSubmatch(pattern, -1) {
+			route.Params = append(route.Params, param[1])
+		}
+		routes = append(routes, route)
+	}
+
+	if len(routes) == 0 {
+		return nil
+	}
+	return routes
+}
+
+// detectRoutes marks Next.js file-system routes, deriving their URL pattern and
+// parameters from bracketed segments like [id] and [...slug]
+func detectRoutes(project *Project, src sourceFS, rootDir string) {
+	if !isNextProject(src, rootDir) {
+		return
+	}
+
+	for id, node := range project.NodesMap {
+		p := ConvertToUnixPath(id)
+		if node.Type == "test" || !isNextRoute(p) {
+			continue
+		}
+
+		node.Type = "route"
+		node.MultipleComp = false
+		node.Routes = []RouteInfo{nextRoutePattern(p)}
+		project.NodesMap[id] = node
+	}
+}
+
+// nextRoutePattern converts a Next.js route file path into a URL pattern
+func nextRoutePattern(p string) RouteInfo {
+	p = strings.TrimPrefix(p, "src/")
+	appRouter := strings.HasPrefix(p, "app/")
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "pages/"), "app/")
+	p = strings.TrimSuffix(p, path.Ext(p))
+
+	route := RouteInfo{}
+	segments := []string{}
+	for _, segment := range strings.Split(p, "/") {
+		switch {
+		case segment == "index" && !appRouter:
+			continue
+		case appRouter && (segment == "page" || segment == "layout" || segment == "route"):
+			continue
+		case strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")"):
+			continue // route groups don't affect the URL
+		case strings.HasPrefix(segment, "@"):
+			continue // parallel route slots don't affect the URL
+		case strings.HasPrefix(segment, "[[...") && strings.HasSuffix(segment, "]]"):
+			name := segment[5 : len(segment)-2]
+			route.Params = append(route.Params, name)
+			segments = append(segments, ":"+name+"*?")
+		case strings.HasPrefix(segment, "[...") && strings.HasSuffix(segment, "]"):
+			name := segment[4 : len(segment)-1]
+			route.Params = append(route.Params, name)
+			segments = append(segments, ":"+name+"*")
+		case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"):
+			name := segment[1 : len(segment)-1]
+			route.Params = append(route.Params, name)
+			segments = append(segments, ":"+name)
+		case segment != "":
+			segments = append(segments, segment)
+		}
+	}
+
+	route.Pattern = "/" + strings.Join(segments, "/")
+	return route
+}
+
+// isNextProject reports whether the project uses Next.js, based on a next.config
+// file or a "next" dependency in package.json
+func isNextProject(src sourceFS, rootDir string) bool {
+	for _, name := range []string{"next.config.js", "next.config.mjs", "next.config.ts"} {
+		if _, err := src.Stat(filepath.Join(rootDir, name)); err == nil {
+			return true
+		}
+	}
+
+	data, err := src.ReadFile(filepath.Join(rootDir, "package.json"))
+	if err != nil {
+		return false
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+	_, inDeps := pkg.Dependencies["next"]
+	_, inDevDeps := pkg.DevDependencies["next"]
+	return inDeps || inDevDeps
+}
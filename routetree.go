@@ -0,0 +1,277 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RouteNode is a route of a React Router data router, with the component it
+// renders and its nested routes
+type RouteNode struct {
+	Path      string      `json:"path,omitempty"`
+	Index     bool        `json:"index,omitempty"`
+	Component string      `json:"component,omitempty"` // node rendered through element, Component or lazy
+	Lazy      bool        `json:"lazy,omitempty"`      // the component is loaded on demand
+	File      string      `json:"file"`                // file declaring the route
+	Children  []RouteNode `json:"children,omitempty"`
+}
+
+// dataRouterRegex matches a data router created from an inline route array
+var dataRouterRegex = regexp.MustCompile(`\bcreate(?:Browser|Hash|Memory)Router\s*\(\s*\[`)
+
+// jsxComponentTagRegex matches the name of a capitalized JSX opening tag
+var jsxComponentTagRegex = regexp.MustCompile(`<([A-Z][\w$]*)`)
+
+// quotedValueRegex matches a string literal at the start of a property value
+var quotedValueRegex = regexp.MustCompile(`^\s*['"]([^'"]*)['"]`)
+
+// lazyDeclRegex matches a variable declared as a React.lazy() component,
+// capturing its name
+var lazyDeclRegex = regexp.MustCompile(`\b(?:const|let|var)\s+([\w$]+)\s*=\s*(?:React\.)?lazy\s*\(`)
+
+// findDataRoutes parses the route arrays a file passes to
+// createBrowserRouter, createHashRouter and createMemoryRouter into route
+// hierarchies, linking each route to the module its element, Component or
+// lazy property loads through the file's import edges
+func findDataRoutes(content string, node ComponentNode) []RouteNode {
+	if !strings.Contains(content, "Router(") {
+		return nil
+	}
+
+	p := routeParser{node: node, content: content, code: stripNonCode(content), lines: lineOffsets(content)}
+	var routes []RouteNode
+	for _, loc := range dataRouterRegex.FindAllStringIndex(p.code, -1) {
+		routes = append(routes, p.routes(loc[1]-1)...)
+	}
+	return routes
+}
+
+// buildRouteTree gathers the data router routes of every file, in ID order,
+// keeping only the components that are project nodes
+func buildRouteTree(project Project) []RouteNode {
+	kept := make(map[string]bool, len(project.NodesMap))
+	for id := range project.NodesMap {
+		kept[id] = true
+	}
+
+	var routes []RouteNode
+	for _, id := range sortedNodeIDs(project) {
+		routes = append(routes, keepRouteTree(project.NodesMap[id].DataRoutes, kept)...)
+	}
+	return routes
+}
+
+// routeParser reads route objects from one file. Structure is read from code,
+// where strings and comments are blanked out, and literals from content at
+// the same offsets.
+type routeParser struct {
+	node    ComponentNode
+	content string
+	code    string
+	lines   []int
+}
+
+// routes parses the array of route objects opening at the bracket at open
+func (p routeParser) routes(open int) []RouteNode {
+	routes := []RouteNode{}
+	end := p.closing(open)
+	for i := open + 1; i < end; i++ {
+		switch p.code[i] {
+		case '{':
+			close := p.closing(i)
+			routes = append(routes, p.route(i, close))
+			i = close
+		case '[', '(':
+			i = p.closing(i)
+		}
+	}
+	return routes
+}
+
+// route parses the route object between the braces at start and end
+func (p routeParser) route(start, end int) RouteNode {
+	route := RouteNode{File: p.node.ID}
+	props := p.properties(start, end)
+
+	if span, ok := props["path"]; ok {
+		if match := quotedValueRegex.FindStringSubmatch(p.content[span[0]:span[1]]); match != nil {
+			route.Path = match[1]
+		}
+	}
+	if span, ok := props["index"]; ok {
+		route.Index = strings.TrimSpace(p.code[span[0]:span[1]]) == "true"
+	}
+
+	if span, ok := props["lazy"]; ok {
+		if target, found := p.importedAt(span[0], span[1]); found {
+			route.Component, route.Lazy = target, true
+		}
+	}
+	if span, ok := props["Component"]; ok && route.Component == "" {
+		value := strings.TrimSpace(p.code[span[0]:span[1]])
+		if target, lazy, found := p.component(value); found {
+			route.Component, route.Lazy = target, lazy
+		} else if target, found := p.importedAt(span[0], span[1]); found {
+			route.Component, route.Lazy = target, true
+		}
+	}
+	if span, ok := props["element"]; ok && route.Component == "" {
+		for _, match := range jsxComponentTagRegex.FindAllStringSubmatch(p.code[span[0]:span[1]], -1) {
+			if target, lazy, found := p.component(match[1]); found {
+				route.Component, route.Lazy = target, lazy
+				break
+			}
+		}
+	}
+
+	if span, ok := props["children"]; ok {
+		if open := strings.IndexByte(p.code[span[0]:span[1]], '['); open >= 0 {
+			route.Children = p.routes(span[0] + open)
+		}
+	}
+	return route
+}
+
+// properties returns the value span of every property at the top level of
+// the object between the braces at start and end. Shorthand properties span
+// their own name and methods such as lazy() { ... } span their parameters
+// and body.
+func (p routeParser) properties(start, end int) map[string][2]int {
+	props := make(map[string][2]int)
+	i := start + 1
+	for i < end {
+		for i < end && (isSpaceByte(p.code[i]) || p.code[i] == ',') {
+			i++
+		}
+		key, j := p.identifier(i, end)
+		if key == "async" {
+			key, j = p.identifier(p.skipSpace(j, end), end)
+		}
+		k := p.skipSpace(j, end)
+
+		switch {
+		case key == "":
+			i = p.valueEnd(i, end) + 1
+		case k < end && p.code[k] == ':':
+			valueEnd := p.valueEnd(k+1, end)
+			props[key] = [2]int{k + 1, valueEnd}
+			i = valueEnd + 1
+		case k < end && p.code[k] == '(':
+			valueEnd := p.valueEnd(k, end)
+			props[key] = [2]int{k, valueEnd}
+			i = valueEnd + 1
+		default:
+			props[key] = [2]int{i, j}
+			i = p.valueEnd(j, end) + 1
+		}
+	}
+	return props
+}
+
+// component returns the module a local name in the file refers to: the target
+// of an import binding it, or the module a lazy() declaration of it loads
+func (p routeParser) component(name string) (string, bool, bool) {
+	for _, edge := range p.node.ImportEdges {
+		if containsString(edge.localBindings(), name) {
+			return edge.Target, false, true
+		}
+	}
+
+	for _, loc := range lazyDeclRegex.FindAllStringSubmatchIndex(p.code, -1) {
+		if p.code[loc[2]:loc[3]] != name {
+			continue
+		}
+		if target, found := p.importedAt(loc[1], min(p.closing(loc[1]-1)+1, len(p.code))); found {
+			return target, true, true
+		}
+		break
+	}
+	return "", false, false
+}
+
+// importedAt returns the module loaded by the first import() call between
+// start and end, matching it to the file's dynamic import edge on that line
+func (p routeParser) importedAt(start, end int) (string, bool) {
+	offset := strings.Index(p.code[start:end], "import(")
+	if offset < 0 {
+		return "", false
+	}
+	line := lineAt(p.lines, start+offset)
+	for _, edge := range p.node.ImportEdges {
+		if edge.Line == line && (edge.Kind == "lazy" || edge.Kind == "dynamic") {
+			return edge.Target, true
+		}
+	}
+	return "", false
+}
+
+// identifier returns the identifier starting at i and the offset after it
+func (p routeParser) identifier(i, end int) (string, int) {
+	j := i
+	for j < end && isIdentPart(p.code[j]) {
+		j++
+	}
+	return p.code[i:j], j
+}
+
+// skipSpace returns the offset of the first non-space byte from i
+func (p routeParser) skipSpace(i, end int) int {
+	for i < end && isSpaceByte(p.code[i]) {
+		i++
+	}
+	return i
+}
+
+// valueEnd returns the offset of the comma ending the value starting at i,
+// or end if it is the last one
+func (p routeParser) valueEnd(i, end int) int {
+	for ; i < end; i++ {
+		switch p.code[i] {
+		case ',':
+			return i
+		case '{', '[', '(':
+			i = p.closing(i)
+		}
+	}
+	return end
+}
+
+// closing returns the offset of the bracket closing the one at open, or the
+// end of the code if it is unbalanced
+func (p routeParser) closing(open int) int {
+	depth := 0
+	for i := open; i < len(p.code); i++ {
+		switch p.code[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(p.code)
+}
+
+// isSpaceByte reports whether c is ASCII whitespace
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// keepRouteTree restricts route trees to the kept nodes: routes declared in
+// dropped files are removed and links to dropped components are cleared
+func keepRouteTree(routes []RouteNode, kept map[string]bool) []RouteNode {
+	var filtered []RouteNode
+	for _, route := range routes {
+		if !kept[route.File] {
+			continue
+		}
+		if !kept[route.Component] {
+			route.Component, route.Lazy = "", false
+		}
+		route.Children = keepRouteTree(route.Children, kept)
+		filtered = append(filtered, route)
+	}
+	return filtered
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNestedRouteTree(t *testing.T) {
+	project := scanFiles(t, map[string]string{
+		"src/router.tsx": `import { lazy } from 'react'
+import { createBrowserRouter } from 'react-router-dom'
+import Layout from './Layout'
+import Home from './pages/Home'
+
+const Settings = lazy(() => import('./pages/Settings'))
+
+export const router = createBrowserRouter([
+  {
+    path: '/',
+    element: <Layout />,
+    children: [
+      { index: true, element: <Home /> },
+      { path: 'settings', element: <Settings /> },
+      {
+        path: 'reports',
+        lazy: () => import('./pages/Reports'),
+      },
+    ],
+  },
+])
+`,
+		"src/Layout.tsx":         "export default function Layout() { return <main /> }\n",
+		"src/pages/Home.tsx":     "export default function Home() { return <main /> }\n",
+		"src/pages/Settings.tsx": "export default function Settings() { return <main /> }\n",
+		"src/pages/Reports.tsx":  "export default function Reports() { return <main /> }\n",
+	}, ScanOptions{})
+
+	file := filepath.FromSlash("src/router.tsx")
+	want := []RouteNode{{
+		Path:      "/",
+		Component: filepath.FromSlash("src/Layout.tsx"),
+		File:      file,
+		Children: []RouteNode{
+			{Index: true, Component: filepath.FromSlash("src/pages/Home.tsx"), File: file},
+			{Path: "settings", Component: filepath.FromSlash("src/pages/Settings.tsx"), Lazy: true, File: file},
+			{Path: "reports", Component: filepath.FromSlash("src/pages/Reports.tsx"), Lazy: true, File: file},
+		},
+	}}
+	if !reflect.DeepEqual(project.RouteTree, want) {
+		t.Errorf("RouteTree = %+v, want %+v", project.RouteTree, want)
+	}
+
+	if routes := nodeAt(t, project, "src/router.tsx").DataRoutes; !reflect.DeepEqual(routes, want) {
+		t.Errorf("DataRoutes = %+v, want the tree parsed with the file", routes)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Server serves a scanned project over HTTP and pushes compact graph patches
+// to subscribers whenever the project changes on disk.
+//
+// Endpoints:
+//
+//	GET /project  the full project JSON
+//	GET /usage    the component usage ranking (see Project.UsageRanking)
+//	GET /events   a server-sent event stream of patches (see ProjectDiff.Patch)
+//
+// A client too slow to keep up with the patches is disconnected rather than
+// left out of sync; it should refetch /project when it reconnects.
+type Server struct {
+	rootDir  string
+	interval time.Duration
+	opts     ScanOptions // scan settings; the logger also receives server diagnostics
+
+	mu          sync.RWMutex
+	project     Project
+	fingerprint string
+	subscribers map[chan []byte]bool
+}
+
+// NewServer scans rootDir with opts and returns a server that re-checks it
+// every interval
+func NewServer(rootDir string, interval time.Duration, opts ScanOptions) (*Server, error) {
+	project, err := ScanProjectWithOptions(rootDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	fingerprint, err := projectFingerprint(rootDir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		rootDir:     rootDir,
+		interval:    interval,
+		opts:        opts,
+		project:     project,
+		fingerprint: fingerprint,
+		subscribers: make(map[chan []byte]bool),
+	}, nil
+}
+
+// Serve scans rootDir, watches it for changes, and serves it on addr until ctx is done
+func Serve(ctx context.Context, addr, rootDir string) error {
+	server, err := NewServer(rootDir, 2*time.Second, DefaultScanOptions())
+	if err != nil {
+		return err
+	}
+
+	go server.Watch(ctx)
+
+	httpServer := &http.Server{Addr: addr, Handler: server}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	server.opts.logger().Info("Serving project", "dir", rootDir, "addr", addr)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/project":
+		s.serveProject(w)
+	case "/usage":
+		s.serveUsage(w)
+	case "/events":
+		s.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveProject streams the current project as JSON
+func (s *Server) serveProject(w http.ResponseWriter) {
+	s.mu.RLock()
+	project := s.project
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := WriteProjectJSON(w, project); err != nil {
+		s.opts.logger().Warn("Failed to write project", "err", err)
+	}
+}
+
+// serveUsage writes the component usage ranking of the current project as JSON
+func (s *Server) serveUsage(w http.ResponseWriter) {
+	s.mu.RLock()
+	ranking := s.project.UsageRanking
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ranking); err != nil {
+		s.opts.logger().Warn("Failed to write usage ranking", "err", err)
+	}
+}
+
+// serveEvents streams patches to the client as server-sent events
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	patches := make(chan []byte, 8)
+	s.mu.Lock()
+	s.subscribers[patches] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, patches)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case patch, ok := <-patches:
+			if !ok {
+				return // fell behind; the client resynchronizes on reconnect
+			}
+			fmt.Fprintf(w, "event: patch\ndata: %s\n\n", patch)
+			flusher.Flush()
+		}
+	}
+}
+
+// Watch polls the project for changes until ctx is done, rescanning and
+// broadcasting a patch whenever the graph changes
+func (s *Server) Watch(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refresh(); err != nil {
+				s.opts.logger().Warn("Failed to rescan project", "err", err)
+			}
+		}
+	}
+}
+
+// refresh rescans the project if any file changed and publishes the resulting patch
+func (s *Server) refresh() error {
+	fingerprint, err := projectFingerprint(s.rootDir, s.opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	unchanged := fingerprint == s.fingerprint
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	project, err := ScanProjectWithOptions(s.rootDir, s.opts)
+	if err != nil {
+		return err
+	}
+	ConvertProjectPathsToUnix(&project)
+
+	s.mu.Lock()
+	diff := DiffProjects(s.project, project)
+	s.project = project
+	s.fingerprint = fingerprint
+	s.mu.Unlock()
+
+	if diff.Empty() {
+		return nil
+	}
+
+	patch, err := diff.Patch()
+	if err != nil {
+		return err
+	}
+	s.broadcast(patch)
+	return nil
+}
+
+// broadcast sends a patch to every subscriber. A subscriber whose buffer is
+// full would miss the patch and drift out of sync, so it is disconnected.
+func (s *Server) broadcast(patch []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- patch:
+		default:
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// fingerprintWindow is how recently a file must have been modified for its
+// content to be part of the fingerprint. Coarse filesystems record modification
+// times in steps of up to two seconds, so an edit within that window may keep
+// both size and time.
+const fingerprintWindow = 2 * time.Second
+
+// projectFingerprint hashes the paths, sizes and modification times of the
+// project's source files and of the config files that affect the scan, in
+// walk order, so changes can be detected without a full scan. Directories
+// are skipped as the scan with opts skips them. Recently modified files
+// contribute their content as well.
+func projectFingerprint(rootDir string, opts ScanOptions) (string, error) {
+	h := sha256.New()
+	now := time.Now()
+
+	err := filepath.Walk(rootDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != rootDir && opts.skipsDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !isReactFile(info.Name()) && !isScanConfigFile(info.Name()) {
+			return nil
+		}
+
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.ToSlash(path), info.Size(), info.ModTime().UnixNano())
+		if now.Sub(info.ModTime()) < fingerprintWindow {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			h.Write(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isScanConfigFile reports whether a file with the given name can change the
+// result of a scan without being a source file: an alias config file or the
+// .reactviz.json sidecar
+func isScanConfigFile(name string) bool {
+	return name == sidecarFile || containsString(projectConfigFiles, name)
+}
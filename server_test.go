@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProjectFingerprint(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"src/App.jsx":    "export default function App() { return <div /> }\n",
+		"src/Button.jsx": "export const label = 'a'\n",
+	})
+	button := filepath.Join(root, "src", "Button.jsx")
+	fingerprint := func() string {
+		t.Helper()
+		value, err := projectFingerprint(root, ScanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	info, err := os.Stat(button)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime := info.ModTime()
+
+	before := fingerprint()
+	if again := fingerprint(); again != before {
+		t.Fatal("fingerprint changed without any change to the files")
+	}
+
+	// An edit keeping the size and modification time
+	if err := os.WriteFile(button, []byte("export const label = 'b'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(button, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	edited := fingerprint()
+	if edited == before {
+		t.Error("same-size edit within the modification time granularity went unnoticed")
+	}
+
+	// A rename keeping the size and modification time
+	renamed := filepath.Join(root, "src", "Badge.jsx")
+	if err := os.Rename(button, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint() == edited {
+		t.Error("rename went unnoticed")
+	}
+}
+
+func TestFingerprintConfigAndSkipDirs(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"tsconfig.json":      `{"compilerOptions": {"baseUrl": "."}}`,
+		"src/App.jsx":        "export default function App() { return <div /> }\n",
+		"generated/Types.ts": "export type A = string\n",
+	})
+	opts := ScanOptions{SkipDirs: []string{"generated"}}
+	fingerprint := func() string {
+		t.Helper()
+		value, err := projectFingerprint(root, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := fingerprint()
+	write("generated/Types.ts", "export type A = number\n")
+	if fingerprint() != before {
+		t.Error("edit in a skipped directory changed the fingerprint")
+	}
+
+	write("tsconfig.json", `{"compilerOptions": {"baseUrl": "src"}}`)
+	if fingerprint() == before {
+		t.Error("tsconfig.json edit went unnoticed")
+	}
+}
+
+func TestBroadcastDisconnectsSlowSubscribers(t *testing.T) {
+	fast := make(chan []byte, 1)
+	slow := make(chan []byte, 1)
+	slow <- []byte("unread")
+	server := &Server{subscribers: map[chan []byte]bool{fast: true, slow: true}}
+
+	server.broadcast([]byte("patch"))
+
+	select {
+	case patch := <-fast:
+		if string(patch) != "patch" {
+			t.Errorf("fast subscriber got %q", patch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("fast subscriber got no patch")
+	}
+
+	<-slow // the patch it could not take up is not queued behind this
+	if _, open := <-slow; open {
+		t.Error("slow subscriber was not disconnected")
+	}
+	if server.subscribers[slow] {
+		t.Error("slow subscriber is still registered")
+	}
+}
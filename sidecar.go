@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// sidecarFile is the name of the per-project curation file in the scanned root
+const sidecarFile = ".reactviz.json"
+
+// Sidecar curates a project's graph across scans. Node IDs are relative to the
+// scanned root, with forward slashes.
+type Sidecar struct {
+	Hide []string `json:"hide,omitempty"` // nodes removed from the graph
+	Pin  []string `json:"pin,omitempty"`  // nodes marked as pinned for highlighting
+
+	// HiddenEdges is "drop" (the default) to remove edges touching hidden
+	// nodes, or "reroute" to link their importers to what they import
+	HiddenEdges string `json:"hiddenEdges,omitempty"`
+}
+
+// readSidecar reads the sidecar file of rootDir, returning nil if there is none
+func readSidecar(src sourceFS, rootDir string) (*Sidecar, error) {
+	data, err := src.ReadFile(filepath.Join(rootDir, sidecarFile))
+	if err != nil {
+		return nil, nil
+	}
+
+	var sidecar Sidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return nil, fmt.Errorf("%s: %w", sidecarFile, err)
+	}
+	if sidecar.HiddenEdges != "" && sidecar.HiddenEdges != "drop" && sidecar.HiddenEdges != "reroute" {
+		return nil, fmt.Errorf("%s: unknown hiddenEdges %q", sidecarFile, sidecar.HiddenEdges)
+	}
+	return &sidecar, nil
+}
+
+// applySidecar pins and hides the nodes listed in the sidecar, returning the
+// curated project and the listed IDs that match no node
+func applySidecar(project Project, sidecar Sidecar) (Project, []string) {
+	unknown := []string{}
+	lookup := func(ids []string) map[string]bool {
+		found := make(map[string]bool)
+		for _, id := range ids {
+			id = filepath.FromSlash(id)
+			if _, exists := project.NodesMap[id]; exists {
+				found[id] = true
+			} else {
+				unknown = append(unknown, ConvertToUnixPath(id))
+			}
+		}
+		return found
+	}
+	hidden := lookup(sidecar.Hide)
+	pinned := lookup(sidecar.Pin)
+
+	for id := range pinned {
+		node := project.NodesMap[id]
+		node.Pinned = true
+		project.NodesMap[id] = node
+	}
+
+	if len(hidden) > 0 {
+		if sidecar.HiddenEdges == "reroute" {
+			rerouteHidden(&project, hidden)
+		}
+		project = subgraph(project, func(node ComponentNode) bool {
+			return !hidden[node.ID]
+		})
+	}
+	return project, unknown
+}
+
+// rerouteHidden links every importer of a hidden node to the visible nodes
+// the hidden one reaches through hidden nodes only, recording it in Via
+func rerouteHidden(project *Project, hidden map[string]bool) {
+	for _, id := range sortedNodeIDs(*project) {
+		if hidden[id] {
+			continue
+		}
+		node := project.NodesMap[id]
+
+		for _, edge := range node.ImportEdges {
+			if !hidden[edge.Target] {
+				continue
+			}
+			for _, target := range visibleThrough(*project, edge.Target, hidden) {
+				if target == id || containsString(node.Imports, target) {
+					continue
+				}
+				node.ImportEdges = append(node.ImportEdges, ImportEdge{Target: target, Kind: edge.Kind, Via: edge.Target, Line: edge.Line})
+				node.Imports = append(node.Imports, target)
+
+				imported := project.NodesMap[target]
+				imported.ImportedBy = append(imported.ImportedBy, id)
+				project.NodesMap[target] = imported
+			}
+		}
+		project.NodesMap[id] = node
+	}
+}
+
+// visibleThrough returns the sorted visible nodes imported by start, directly
+// or through a chain of other hidden nodes
+func visibleThrough(project Project, start string, hidden map[string]bool) []string {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	visible := make(map[string]bool)
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range project.NodesMap[id].Imports {
+			if _, exists := project.NodesMap[next]; !exists || visited[next] {
+				continue
+			}
+			visited[next] = true
+			if hidden[next] {
+				queue = append(queue, next)
+			} else {
+				visible[next] = true
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(visible))
+	for id := range visible {
+		targets = append(targets, id)
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// ScanStaged scans the files staged in Git under rootDir, as they are in the
+// index, plus the project files they import directly or transitively on
+// either side of the commit, which is enough to see every cycle passing
+// through a staged file. It is
+// meant for pre-commit hooks: Cycles and BarrelChains list only findings
+// involving a staged file, and Cycles leaves out cycles that already exist on
+// HEAD. Files lists the staged files themselves. Skipped directories, alias
+// overrides and the sidecar are honoured as in a full scan. Fan-in counts
+// cover just the parsed files, so god-component linting needs a full scan.
+func ScanStaged(rootDir string, opts ScanOptions) (Project, error) {
+	staged, err := gitStagedFiles(rootDir)
+	if err != nil {
+		return Project{}, err
+	}
+
+	logger := opts.logger()
+	src := newSourceFS(rootDir, opts.FS)
+
+	project := Project{
+		Root: ComponentNode{
+			ID:   "root",
+			Name: filepath.Base(rootDir),
+			Path: rootDir,
+			Type: "root",
+		},
+		NodesMap: make(map[string]ComponentNode),
+		Files:    []string{},
+	}
+	warn := func(format string, args ...any) {
+		message := fmt.Sprintf(format, args...)
+		project.ScanWarnings = append(project.ScanWarnings, message)
+		logger.Warn(message)
+	}
+	aliasConfig := scanAliasConfig(src, rootDir, opts, warn)
+
+	// Parse the staged files as they are in the index and as on HEAD, then
+	// whatever either version reaches through imports. Unstaged files are the
+	// same on both sides.
+	isStaged := make(map[string]bool)
+	headNodes := make(map[string]ComponentNode)
+	queue := []string{}
+	for _, relPath := range staged {
+		if !isReactFile(filepath.Base(relPath)) || opts.skipsPath(relPath) {
+			continue
+		}
+		project.Files = append(project.Files, relPath)
+		isStaged[relPath] = true
+		queue = append(queue, relPath)
+
+		if content, ok := gitFile(rootDir, "HEAD", relPath); ok {
+			node, err := parseContent(context.Background(), src, content, filepath.Join(rootDir, relPath), relPath, rootDir, aliasConfig, opts)
+			if err != nil {
+				return project, err
+			}
+			if node.Name != "" {
+				headNodes[relPath] = node
+				queue = append(queue, node.Imports...)
+			}
+		}
+	}
+
+	parsed := make(map[string]bool)
+	for len(queue) > 0 {
+		relPath := queue[0]
+		queue = queue[1:]
+		if parsed[relPath] || isOutsideRoot(relPath) || !isReactFile(filepath.Base(relPath)) || opts.skipsPath(relPath) {
+			continue
+		}
+		parsed[relPath] = true
+
+		// Staged files are read from the index, so unstaged edits are left out
+		path := filepath.Join(rootDir, relPath)
+		var node ComponentNode
+		if isStaged[relPath] {
+			content, ok := gitFile(rootDir, "", relPath)
+			if !ok {
+				continue
+			}
+			node, err = parseContent(context.Background(), src, content, path, relPath, rootDir, aliasConfig, opts)
+		} else {
+			if info, statErr := src.Stat(path); statErr != nil || info.IsDir() {
+				continue
+			}
+			node, err = parseFile(context.Background(), src, path, relPath, rootDir, aliasConfig, opts)
+		}
+		if err != nil {
+			return project, err
+		}
+		project.ScanMetrics.FileCount++
+		if node.Name != "" {
+			project.NodesMap[node.ID] = node
+		}
+		queue = append(queue, node.Imports...)
+	}
+
+	head := Project{NodesMap: make(map[string]ComponentNode)}
+	for id, node := range project.NodesMap {
+		if !isStaged[id] {
+			head.NodesMap[id] = node
+		}
+	}
+	for id, node := range headNodes {
+		head.NodesMap[id] = node
+	}
+
+	// Hidden nodes are left out on both sides
+	sidecar, err := readSidecar(src, rootDir)
+	if err != nil {
+		warn("Ignoring %v", err)
+	}
+	for _, graph := range []*Project{&project, &head} {
+		linkRegistryMembers(graph)
+		buildRelationships(graph)
+		if sidecar != nil {
+			*graph, _ = applySidecar(*graph, *sidecar)
+		}
+	}
+
+	// Keep only the findings a staged file takes part in
+	project.BarrelChains, project.Clusters, project.Cycles = nil, nil, nil
+	for _, chain := range findBarrelChains(project) {
+		for _, id := range append([]string{chain.Leaf}, chain.Chain...) {
+			if isStaged[id] {
+				project.BarrelChains = append(project.BarrelChains, chain)
+				break
+			}
+		}
+	}
+	headCycles := StronglyConnectedComponents(head)
+	for _, cycle := range classifyCycles(StronglyConnectedComponents(project)) {
+		if !touchesAny(cycle.Nodes, isStaged) || withinAny(cycle.Nodes, headCycles) {
+			continue
+		}
+		project.Clusters = append(project.Clusters, cycle.Nodes)
+		project.Cycles = append(project.Cycles, cycle)
+	}
+	return project, nil
+}
+
+// touchesAny reports whether any of ids is in set
+func touchesAny(ids []string, set map[string]bool) bool {
+	for _, id := range ids {
+		if set[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// withinAny reports whether one of the clusters contains every id, so a cycle
+// through ids already existed there
+func withinAny(ids []string, clusters [][]string) bool {
+	for _, cluster := range clusters {
+		contained := true
+		for _, id := range ids {
+			if !containsString(cluster, id) {
+				contained = false
+				break
+			}
+		}
+		if contained {
+			return true
+		}
+	}
+	return false
+}
+
+// gitStagedFiles returns the files added, copied, modified or renamed in the
+// Git index, relative to rootDir. Files outside rootDir are left out.
+func gitStagedFiles(rootDir string) ([]string, error) {
+	cmd := exec.Command("git", "-C", rootDir, "diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMR")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing staged files of %s: %w", rootDir, err)
+	}
+
+	files := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			files = append(files, filepath.FromSlash(line))
+		}
+	}
+	return files, scanner.Err()
+}
+
+// gitFile returns the content of relPath, relative to rootDir, in the commit
+// rev, or in the index when rev is empty. It reports false for files that
+// version doesn't have, such as new files on HEAD or any file before the
+// first commit.
+func gitFile(rootDir, rev, relPath string) (string, bool) {
+	cmd := exec.Command("git", "-C", rootDir, "show", rev+":./"+filepath.ToSlash(relPath))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	return string(output), true
+}
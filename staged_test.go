@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// git runs a git command in dir, failing the test on error
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func TestScanStaged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := writeFiles(t, map[string]string{
+		"src/a.js":       "import { b } from './b'\nexport const a = 1\n",
+		"src/b.js":       "import { a } from './a'\nexport const b = 1\n",
+		"src/c.js":       "export const c = 1\n",
+		"src/d.js":       "import { c } from './c'\nexport const d = 1\n",
+		"src/e.js":       "export const e = 1\n",
+		"src/f.js":       "import { e } from './e'\nexport const f = 1\n",
+		"legacy/x.js":    "export const x = 1\n",
+		"legacy/y.js":    "import { x } from './x'\nexport const y = 1\n",
+		".reactviz.json": `{"hide": ["src/f.js"]}`,
+	})
+	git(t, dir, "init", "-q")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+
+	// a <-> b already cycles on HEAD; c <-> d is new; e <-> f runs through a
+	// hidden file and x <-> y lies in a skipped directory
+	edits := map[string]string{
+		"src/a.js":    "import { b } from './b'\nexport const a = 2\n",
+		"src/c.js":    "import { d } from './d'\nexport const c = 1\n",
+		"src/e.js":    "import { f } from './f'\nexport const e = 1\n",
+		"legacy/x.js": "import { y } from './y'\nexport const x = 1\n",
+	}
+	for name, content := range edits {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, dir, "add", "-A")
+
+	project, err := ScanStaged(dir, ScanOptions{Quiet: true, SkipDirs: []string{"legacy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join("src", "c.js"), filepath.Join("src", "d.js")}
+	if len(project.Cycles) != 1 || !reflect.DeepEqual(sortedCopy(project.Cycles[0].Nodes), want) {
+		t.Errorf("cycles = %+v, want only %v", project.Cycles, want)
+	}
+	if got := sortedCopy(project.Files); !reflect.DeepEqual(got, []string{filepath.Join("src", "a.js"), filepath.Join("src", "c.js"), filepath.Join("src", "e.js")}) {
+		t.Errorf("files = %v, want the staged files outside legacy", got)
+	}
+}
+
+func TestScanStagedReadsIndex(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := writeFiles(t, map[string]string{
+		"src/a.js": "export const a = 1\n",
+		"src/b.js": "import { a } from './a'\nexport const b = 1\n",
+		"src/c.js": "export const c = 1\n",
+		"src/d.js": "import { c } from './c'\nexport const d = 1\n",
+	})
+	git(t, dir, "init", "-q")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+
+	// a <-> b is staged and then undone in the worktree; c <-> d is only
+	// in the worktree, behind a staged edit that doesn't create it
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("src/a.js", "import { b } from './b'\nexport const a = 1\n")
+	write("src/c.js", "export const c = 2\n")
+	git(t, dir, "add", "-A")
+	write("src/a.js", "export const a = 1\n")
+	write("src/c.js", "import { d } from './d'\nexport const c = 2\n")
+
+	project, err := ScanStaged(dir, ScanOptions{Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join("src", "a.js"), filepath.Join("src", "b.js")}
+	if len(project.Cycles) != 1 || !reflect.DeepEqual(sortedCopy(project.Cycles[0].Nodes), want) {
+		t.Errorf("cycles = %+v, want only the staged %v", project.Cycles, want)
+	}
+}
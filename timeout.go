@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errParseTimeout is returned by withTimeout when the work outlives its deadline
+var errParseTimeout = errors.New("parse timed out")
+
+// archiveParseTimeout bounds parsing of each file in archives, whose contents
+// are not under the user's control
+const archiveParseTimeout = 5 * time.Second
+
+// withTimeout runs fn with a context that expires after timeout, returning
+// errParseTimeout if the deadline passed before fn returned. A zero timeout
+// runs fn without a deadline. Go cannot interrupt a running regex, so fn runs
+// on the calling goroutine and is expected to check ctx between its steps and
+// give up early; nothing is left running once withTimeout returns.
+func withTimeout[T any](timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	value, err := fn(ctx)
+	if ctx.Err() != nil {
+		var zero T
+		return zero, errParseTimeout
+	}
+	return value, err
+}
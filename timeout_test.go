@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	// A worker checking its context gives up once the deadline passes
+	steps := 0
+	_, err := withTimeout(10*time.Millisecond, func(ctx context.Context) (int, error) {
+		for ctx.Err() == nil {
+			steps++
+			time.Sleep(time.Millisecond)
+		}
+		return steps, ctx.Err()
+	})
+	if !errors.Is(err, errParseTimeout) {
+		t.Errorf("err = %v, want errParseTimeout", err)
+	}
+
+	// A late result is discarded
+	value, err := withTimeout(time.Millisecond, func(context.Context) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "late", nil
+	})
+	if value != "" || !errors.Is(err, errParseTimeout) {
+		t.Errorf("late result = %q, %v; want errParseTimeout", value, err)
+	}
+
+	value, err = withTimeout(time.Second, func(context.Context) (string, error) { return "ok", nil })
+	if value != "ok" || err != nil {
+		t.Errorf("result = %q, %v; want ok", value, err)
+	}
+	value, err = withTimeout(0, func(context.Context) (string, error) { return "ok", nil })
+	if value != "ok" || err != nil {
+		t.Errorf("result without timeout = %q, %v; want ok", value, err)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines grew from %d to %d", before, after)
+	}
+}
+
+func TestProjectConfigStopsWithContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tsconfig.json":  &fstest.MapFile{Data: []byte(`{"compilerOptions": {"paths": {"@ui/*": ["src/ui/*"]}}}`)},
+		"vite.config.js": &fstest.MapFile{Data: []byte("export default { resolve: { alias: { '@lib': 'src/lib' } } }\n")},
+	}
+	src := newSourceFS("app", fsys)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := readProjectConfig(ctx, src, "app"); !errors.Is(err, context.Canceled) {
+		t.Errorf("readProjectConfig err = %v, want context.Canceled", err)
+	}
+
+	config := AliasConfig{Aliases: make(map[string]string)}
+	if err := parseJSConfig(ctx, src, filepath.Join("app", "vite.config.js"), &config); !errors.Is(err, context.Canceled) {
+		t.Errorf("parseJSConfig err = %v, want context.Canceled", err)
+	}
+	if len(config.Aliases) != 0 {
+		t.Errorf("aliases = %v, want none read after cancellation", config.Aliases)
+	}
+
+	config, err := readProjectConfig(context.Background(), src, "app")
+	if err != nil || config.Aliases["@ui"] == "" || config.Aliases["@lib"] == "" {
+		t.Errorf("readProjectConfig = %+v, %v; want both aliases", config, err)
+	}
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// jsTokenKind classifies a token produced by tokenizeJS
+type jsTokenKind int
+
+const (
+	jsIdent    jsTokenKind = iota // identifiers and keywords
+	jsString                      // string literal; text is the unquoted value
+	jsTemplate                    // template literal; text is the raw body between backticks
+	jsNumber                      // numeric literal
+	jsRegex                       // regular expression literal
+	jsPunct                       // any other single character
+)
+
+// jsToken is a significant token of a JS/TS source file. Comments and
+// whitespace are dropped.
+type jsToken struct {
+	kind jsTokenKind
+	text string
+	pos  int // byte offset of the token in the source
+	end  int // byte offset just past the token
+}
+
+// errUnterminated is returned when a comment or template literal never ends
+var errUnterminated = errors.New("unterminated comment or template literal")
+
+// regexPrecedingKeywords are keywords after which a slash starts a regex literal
+var regexPrecedingKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "of": true,
+	"new": true, "delete": true, "void": true, "throw": true, "yield": true, "await": true, "instanceof": true,
+}
+
+// tokenizeJS splits JS/TS source into tokens, skipping comments and keeping
+// string, template and regex literals whole. It is a lexer only: JSX text is
+// tokenized like code, which is harmless for finding imports.
+func tokenizeJS(src string) ([]jsToken, error) {
+	tokens := []jsToken{}
+	i := 0
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errUnterminated
+			}
+			i += end + 4
+
+		case c == '\'' || c == '"':
+			value, end, ok := scanQuoted(src, i)
+			if !ok {
+				// Strings can't span lines, so this is likely an apostrophe in JSX text
+				tokens = append(tokens, jsToken{kind: jsPunct, text: string(c), pos: i, end: i + 1})
+				i++
+				continue
+			}
+			tokens = append(tokens, jsToken{kind: jsString, text: value, pos: i, end: end})
+			i = end
+
+		case c == '`':
+			end, err := skipTemplate(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, jsToken{kind: jsTemplate, text: src[i+1 : end-1], pos: i, end: end})
+			i = end
+
+		case c == '/' && !closesJSXTag(tokens, i) && slashStartsRegex(tokens):
+			end, ok := scanRegex(src, i)
+			if !ok {
+				tokens = append(tokens, jsToken{kind: jsPunct, text: "/", pos: i, end: i + 1})
+				i++
+				continue
+			}
+			tokens = append(tokens, jsToken{kind: jsRegex, text: src[i:end], pos: i, end: end})
+			i = end
+
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, jsToken{kind: jsIdent, text: src[start:i], pos: start, end: i})
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, jsToken{kind: jsNumber, text: src[start:i], pos: start, end: i})
+
+		default:
+			tokens = append(tokens, jsToken{kind: jsPunct, text: string(c), pos: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// isIdentStart reports whether c can start an identifier. Non-ASCII bytes
+// are accepted so identifiers with Unicode letters stay whole.
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// isIdentPart reports whether c can continue an identifier
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// scanQuoted reads a quoted string starting at i, returning its unescaped
+// value and the offset after the closing quote
+func scanQuoted(src string, i int) (string, int, bool) {
+	quote := src[i]
+	var b strings.Builder
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case quote:
+			return b.String(), j + 1, true
+		case '\n':
+			return "", 0, false
+		case '\\':
+			if j+1 < len(src) {
+				j++
+				b.WriteByte(src[j])
+			}
+		default:
+			b.WriteByte(src[j])
+		}
+	}
+	return "", 0, false
+}
+
+// skipTemplate returns the offset after the template literal starting at i,
+// skipping over nested ${...} expressions
+func skipTemplate(src string, i int) (int, error) {
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case src[j] == '\\':
+			j++
+		case src[j] == '`':
+			return j + 1, nil
+		case strings.HasPrefix(src[j:], "${"):
+			end, err := skipExpression(src, j+2)
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+		}
+	}
+	return 0, errUnterminated
+}
+
+// skipExpression returns the offset after the } closing a template
+// substitution that starts at i
+func skipExpression(src string, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(src); j++ {
+		switch src[j] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return j + 1, nil
+			}
+			depth--
+		case '\'', '"':
+			if _, end, ok := scanQuoted(src, j); ok {
+				j = end - 1
+			}
+		case '`':
+			end, err := skipTemplate(src, j)
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+		}
+	}
+	return 0, errUnterminated
+}
+
+// slashStartsRegex reports whether a slash following tokens begins a regex
+// literal rather than a division
+func slashStartsRegex(tokens []jsToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	prev := tokens[len(tokens)-1]
+	switch prev.kind {
+	case jsIdent:
+		return regexPrecedingKeywords[prev.text]
+	case jsPunct:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return false
+}
+
+// closesJSXTag reports whether the slash at i directly follows a "<", as in
+// the closing tag </p>, which is never a regex literal in JSX
+func closesJSXTag(tokens []jsToken, i int) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	prev := tokens[len(tokens)-1]
+	return prev.kind == jsPunct && prev.text == "<" && prev.end == i
+}
+
+// scanRegex returns the offset after the regex literal starting at i, or
+// false if the line ends first
+func scanRegex(src string, i int) (int, bool) {
+	inClass := false
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '\n':
+			return 0, false
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				end := j + 1
+				for end < len(src) && isIdentPart(src[end]) {
+					end++ // flags
+				}
+				return end, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// stripNonCode blanks out comments, regex literals and the contents of string
+// literals that can't be module specifiers, so regexes only see code. Strings
+// in specifier position are kept, see specifierPosition. The result
+// has the same length and line breaks as src; src is returned unchanged if it
+// can't be tokenized.
+func stripNonCode(src string) string {
+	toks, err := tokenizeJS(src)
+	if err != nil {
+		return src
+	}
+
+	out := []byte(src)
+	for i := range out {
+		if out[i] != '\n' {
+			out[i] = ' '
+		}
+	}
+
+	for i, t := range toks {
+		keep := true
+		switch t.kind {
+		case jsRegex:
+			keep = false
+		case jsString, jsTemplate:
+			keep = specifierPosition(toks, i)
+			if !keep {
+				// Keep the quotes so the literal still reads as a (blank) string
+				out[t.pos] = src[t.pos]
+				out[t.end-1] = src[t.end-1]
+			}
+		}
+		if keep {
+			copy(out[t.pos:t.end], src[t.pos:t.end])
+		}
+	}
+
+	return string(out)
+}
+
+// specifierPosition reports whether the string token at i can be a module
+// specifier: it directly follows from or import, or opens the arguments of
+// import(), require() or new URL()
+func specifierPosition(toks []jsToken, i int) bool {
+	at := func(j int, kind jsTokenKind, text string) bool {
+		return j >= 0 && toks[j].kind == kind && toks[j].text == text
+	}
+	if at(i-1, jsIdent, "from") || at(i-1, jsIdent, "import") {
+		return true
+	}
+	if !at(i-1, jsPunct, "(") || at(i-3, jsPunct, ".") {
+		return false
+	}
+	return at(i-2, jsIdent, "import") || at(i-2, jsIdent, "require") || at(i-2, jsIdent, "URL")
+}
+
+// tokenImport is a module reference found by tokenImports
+type tokenImport struct {
+	specifier string
+	template  string // raw template body for templated dynamic imports
+	edge      ImportEdge
+	pos       int
+}
+
+// tokenImports finds the module references of a JS/TS file from its tokens:
+// static, side-effect and type imports, re-exports, import-equals
+// declarations, require() and import() calls, and worker URLs
+func tokenImports(content string) ([]tokenImport, error) {
+	toks, err := tokenizeJS(content)
+	if err != nil {
+		return nil, err
+	}
+
+	at := func(i int, kind jsTokenKind, text string) bool {
+		return i >= 0 && i < len(toks) && toks[i].kind == kind && (text == "" || toks[i].text == text)
+	}
+	// call matches name ( 'specifier' ) starting at i
+	call := func(i int) bool {
+		return at(i+1, jsPunct, "(") && at(i+2, jsString, "") && at(i+3, jsPunct, ")")
+	}
+
+	found := []tokenImport{}
+	skipRequire := make(map[int]bool)
+
+	for i, t := range toks {
+		if t.kind != jsIdent || at(i-1, jsPunct, ".") {
+			continue
+		}
+
+		switch t.text {
+		case "import":
+			switch {
+			case call(i):
+				found = append(found, tokenImport{specifier: toks[i+2].text, edge: ImportEdge{Kind: "dynamic"}, pos: t.pos})
+			case at(i+1, jsPunct, "(") && at(i+2, jsTemplate, "") && at(i+3, jsPunct, ")"):
+				if strings.Contains(toks[i+2].text, "${") {
+					found = append(found, tokenImport{template: toks[i+2].text, pos: t.pos})
+				} else {
+					found = append(found, tokenImport{specifier: toks[i+2].text, edge: ImportEdge{Kind: "dynamic"}, pos: t.pos})
+				}
+			case at(i+1, jsString, ""):
+				found = append(found, tokenImport{specifier: toks[i+1].text, edge: ImportEdge{Kind: "import"}, pos: t.pos})
+			case at(i+1, jsIdent, "") && at(i+2, jsPunct, "=") && at(i+3, jsIdent, "require") && call(i+3):
+				skipRequire[i+3] = true
+				found = append(found, tokenImport{specifier: toks[i+5].text, edge: ImportEdge{Kind: "import", Default: toks[i+1].text}, pos: t.pos})
+			default:
+				for j := i + 1; j < len(toks); j++ {
+					if at(j, jsIdent, "from") && at(j+1, jsString, "") {
+						edge := ImportEdge{Kind: "import"}
+						parseImportClause(joinTokens(toks[i+1:j]), &edge)
+						found = append(found, tokenImport{specifier: toks[j+1].text, edge: edge, pos: t.pos})
+						break
+					}
+					if at(j, jsPunct, ";") || at(j, jsIdent, "import") || at(j, jsIdent, "export") || at(j, jsPunct, "(") {
+						break
+					}
+				}
+			}
+
+		case "export":
+			j := i + 1
+			if at(j, jsIdent, "type") {
+				j++
+			}
+			switch {
+			case at(j, jsPunct, "*") && at(j+1, jsIdent, "from") && at(j+2, jsString, ""):
+				found = append(found, tokenImport{specifier: toks[j+2].text, edge: ImportEdge{Kind: "reexport", Star: true}, pos: t.pos})
+			case at(j, jsPunct, "*") && at(j+1, jsIdent, "as") && at(j+2, jsIdent, "") && at(j+3, jsIdent, "from") && at(j+4, jsString, ""):
+				edge := ImportEdge{Kind: "reexport", Reexports: []ExportAlias{{Local: "*", Exported: toks[j+2].text}}}
+				found = append(found, tokenImport{specifier: toks[j+4].text, edge: edge, pos: t.pos})
+			case at(j, jsPunct, "{"):
+				k := j + 1
+				for k < len(toks) && !at(k, jsPunct, "}") {
+					k++
+				}
+				if at(k+1, jsIdent, "from") && at(k+2, jsString, "") {
+					edge := ImportEdge{Kind: "reexport", Reexports: parseExportList(joinTokens(toks[j+1 : k]))}
+					found = append(found, tokenImport{specifier: toks[k+2].text, edge: edge, pos: t.pos})
+				}
+			}
+
+		case "require":
+			if call(i) && !skipRequire[i] {
+				found = append(found, tokenImport{specifier: toks[i+2].text, edge: ImportEdge{Kind: "require"}, pos: t.pos})
+			}
+
+		case "URL":
+			// new URL('./worker.ts', import.meta.url)
+			if at(i-1, jsIdent, "new") && at(i+1, jsPunct, "(") && at(i+2, jsString, "") && at(i+3, jsPunct, ",") &&
+				at(i+4, jsIdent, "import") && at(i+5, jsPunct, ".") && at(i+6, jsIdent, "meta") {
+				found = append(found, tokenImport{specifier: toks[i+2].text, edge: ImportEdge{Kind: "worker"}, pos: t.pos})
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// tokenExports returns the sorted names a file exports, read from its tokens
+func tokenExports(content string) ([]string, error) {
+	toks, err := tokenizeJS(content)
+	if err != nil {
+		return nil, err
+	}
+
+	at := func(i int, kind jsTokenKind, text string) bool {
+		return i >= 0 && i < len(toks) && toks[i].kind == kind && (text == "" || toks[i].text == text)
+	}
+	declarations := map[string]bool{
+		"const": true, "let": true, "var": true, "function": true, "class": true,
+		"type": true, "interface": true, "enum": true, "async": true, "declare": true, "abstract": true,
+	}
+
+	names := make(map[string]bool)
+	for i, t := range toks {
+		if t.kind != jsIdent || t.text != "export" || at(i-1, jsPunct, ".") {
+			continue
+		}
+
+		j := i + 1
+		switch {
+		case at(j, jsIdent, "default"):
+			names["default"] = true
+		case at(j, jsPunct, "*") && at(j+1, jsIdent, "as") && at(j+2, jsIdent, ""):
+			names[toks[j+2].text] = true
+		case at(j, jsPunct, "*"):
+			// export * from forwards names without declaring any here
+		case at(j, jsPunct, "{") || (at(j, jsIdent, "type") && at(j+1, jsPunct, "{")):
+			if at(j, jsIdent, "type") {
+				j++
+			}
+			k := j + 1
+			for k < len(toks) && !at(k, jsPunct, "}") {
+				k++
+			}
+			for _, alias := range parseExportList(joinTokens(toks[j+1 : k])) {
+				names[alias.Exported] = true
+			}
+		default:
+			// Skip declaration keywords and generator stars to reach the name
+			for at(j, jsIdent, "") && declarations[toks[j].text] || at(j, jsPunct, "*") {
+				j++
+			}
+			if at(j, jsIdent, "") {
+				names[toks[j].text] = true
+			}
+		}
+	}
+
+	return sortedKeys(names), nil
+}
+
+// joinTokens rebuilds source text from tokens, separated by spaces
+func joinTokens(toks []jsToken) string {
+	texts := make([]string, len(toks))
+	for i, t := range toks {
+		texts[i] = t.text
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTokenizeJSXClosingTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		regex bool
+	}{
+		{"closing tag", "const a = <p>text</p>; import b from './b'", false},
+		{"self-closing tag", "const a = <br/>; import b from './b'", false},
+		{"regex literal", "const a = /ab+c/.test(x); import b from './b'", true},
+		{"regex after paren", "x.replace(/</g, ''); import b from './b'", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toks, err := tokenizeJS(tt.src)
+			if err != nil {
+				t.Fatalf("tokenizeJS: %v", err)
+			}
+			regex := false
+			for _, tok := range toks {
+				if tok.kind == jsRegex {
+					regex = true
+				}
+			}
+			if regex != tt.regex {
+				t.Errorf("regex literal found = %v, want %v", regex, tt.regex)
+			}
+
+			imports, err := tokenImports(tt.src)
+			if err != nil {
+				t.Fatalf("tokenImports: %v", err)
+			}
+			if len(imports) != 1 || imports[0].specifier != "./b" {
+				t.Errorf("imports = %+v, want one import of ./b", imports)
+			}
+		})
+	}
+}
+
+func TestTokenParserParity(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"static imports", "import React, { useState as useS } from 'react'\nimport * as utils from './utils'\nimport './global.css'\nimport Button from './Button'\n"},
+		{"multi-line import", "import {\n  Card,\n  CardBody,\n} from './Card'\n"},
+		{"jsx closing tag", "const a = <p>text</p>\nimport b from './b'\nexport const C = () => <div><b/></div>\n"},
+		{"regex literal", "const quote = /['\"]/g\nimport b from './b'\nexport const strip = (s) => s.replace(/<[^>]*>/g, '')\n"},
+		{"template literal", "const label = `import x from './fake'`\nconst lazy = () => import(`./pages/${name}`)\nexport const t = `${label}`\n"},
+		{"dynamic and require", "const Page = lazy(() => import('./Page'))\nconst fs = require('fs')\nconst util = require('./util')\n"},
+		{"re-exports", "export { Button as PrimaryButton } from './Button'\nexport * from './Card'\nexport * as icons from './icons'\n"},
+		{"import equals", "import Foo = require('./foo')\nexport = Foo\n"},
+		{"worker url", "new Worker(new URL('./worker.ts', import.meta.url))\n"},
+		{"declarations", "export default function App() {}\nexport const a = 1, b = 2\nexport async function load() {}\nexport class Store {}\nexport { a as alpha }\n"},
+	}
+
+	src := mapSource("src/b.js", "src/Button.jsx", "src/Card.jsx", "src/Page.jsx", "src/util.js", "src/foo.ts",
+		"src/utils.js", "src/global.css", "src/icons.js", "src/worker.ts", "src/pages/Home.jsx")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regexEdges, regexExternals := extractImports(src, tt.content, "src", "app", AliasConfig{}, ScanOptions{})
+			tokenEdges, tokenExternals := extractImports(src, tt.content, "src", "app", AliasConfig{}, ScanOptions{UseAST: true})
+			if !sameEdges(regexEdges, tokenEdges) {
+				t.Errorf("edges differ:\nregex %+v\ntoken %+v", regexEdges, tokenEdges)
+			}
+			if !reflect.DeepEqual(sortedCopy(regexExternals), sortedCopy(tokenExternals)) {
+				t.Errorf("externals differ: regex %v, token %v", regexExternals, tokenExternals)
+			}
+
+			exports, err := tokenExports(tt.content)
+			if err != nil {
+				t.Fatalf("tokenExports: %v", err)
+			}
+			if want := findExports(tt.content); !reflect.DeepEqual(exports, want) {
+				t.Errorf("exports differ: regex %v, token %v", want, exports)
+			}
+		})
+	}
+}
+
+// sameEdges reports whether two edge lists hold the same edges in any order
+func sameEdges(a, b []ImportEdge) bool {
+	key := func(edges []ImportEdge) []string {
+		keys := make([]string, len(edges))
+		for i, edge := range edges {
+			keys[i] = fmt.Sprintf("%+v", edge)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	return reflect.DeepEqual(key(a), key(b))
+}
+
+// sortedCopy returns a sorted copy of values
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestIgnoredImportText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"line comment", "// import Old from './a'\nimport b from './b'\n"},
+		{"block comment", "/* const old = require('./a')\n   import('./a') */\nimport b from './b'\n"},
+		{"string argument", "console.log(\"import x from './a'\")\nimport b from './b'\n"},
+		{"require in string", "foo(\"require('./a')\")\nimport b from './b'\n"},
+		{"call with string", "track('./a')\nimport b from './b'\n"},
+		{"template text", "const doc = `import('./a')`\nimport b from './b'\n"},
+	}
+
+	src := mapSource("src/a.js", "src/b.js")
+	for _, tt := range tests {
+		for _, useAST := range []bool{false, true} {
+			edges, _ := extractImports(src, tt.content, "src", "app", AliasConfig{}, ScanOptions{UseAST: useAST})
+			if len(edges) != 1 || edges[0].Target != filepath.Join("src", "b.js") {
+				t.Errorf("%s, UseAST=%v: edges = %+v, want only src/b.js", tt.name, useAST, edges)
+			}
+		}
+	}
+}
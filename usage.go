@@ -0,0 +1,53 @@
+package main
+
+import "sort"
+
+// ComponentUsage counts how widely a component is adopted across the project
+type ComponentUsage struct {
+	ID        string `json:"id"`
+	Importers int    `json:"importers"` // distinct files importing the component
+	Renders   int    `json:"renders"`   // JSX tags and createElement calls rendering it
+}
+
+// usageRanking returns the usage of every component and route node, most
+// imported first, then most rendered. Unused components rank last with zero
+// counts, which is often what a design-system audit is looking for.
+func usageRanking(project Project) []ComponentUsage {
+	usage := make(map[string]*ComponentUsage)
+	for _, id := range sortedNodeIDs(project) {
+		if node := project.NodesMap[id]; node.Type == "component" || node.Type == "route" {
+			usage[id] = &ComponentUsage{ID: id}
+		}
+	}
+
+	for _, id := range sortedNodeIDs(project) {
+		counted := make(map[string]bool)
+		for _, edge := range project.NodesMap[id].ImportEdges {
+			entry, ok := usage[edge.Target]
+			if !ok || edge.Target == id {
+				continue
+			}
+			if !counted[edge.Target] {
+				counted[edge.Target] = true
+				entry.Importers++
+			}
+			entry.Renders += edge.RenderCount
+		}
+	}
+
+	ranking := make([]ComponentUsage, 0, len(usage))
+	for _, entry := range usage {
+		ranking = append(ranking, *entry)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if a.Importers != b.Importers {
+			return a.Importers > b.Importers
+		}
+		if a.Renders != b.Renders {
+			return a.Renders > b.Renders
+		}
+		return a.ID < b.ID
+	})
+	return ranking
+}
@@ -0,0 +1,49 @@
+package main
+
+import "fmt"
+
+// Validate checks the project graph for internal consistency and returns every
+// problem found: node IDs that don't match their NodesMap key, duplicate files,
+// self-imports, and imports without a matching ImportedBy entry or vice versa.
+func (p Project) Validate() []error {
+	var errs []error
+
+	seenFiles := make(map[string]bool)
+	for _, file := range p.Files {
+		if seenFiles[file] {
+			errs = append(errs, fmt.Errorf("file %s is listed more than once", file))
+		}
+		seenFiles[file] = true
+	}
+
+	for _, id := range sortedNodeIDs(p) {
+		node := p.NodesMap[id]
+		if node.ID != id {
+			errs = append(errs, fmt.Errorf("node %s is stored under key %s", node.ID, id))
+		}
+
+		for _, target := range node.Imports {
+			if target == id {
+				errs = append(errs, fmt.Errorf("node %s imports itself", id))
+				continue
+			}
+			imported, exists := p.NodesMap[target]
+			if exists && !containsString(imported.ImportedBy, id) {
+				errs = append(errs, fmt.Errorf("node %s imports %s but is missing from its importedBy", id, target))
+			}
+		}
+
+		for _, importer := range node.ImportedBy {
+			source, exists := p.NodesMap[importer]
+			if !exists {
+				errs = append(errs, fmt.Errorf("node %s is imported by unknown node %s", id, importer))
+				continue
+			}
+			if !containsString(source.Imports, id) {
+				errs = append(errs, fmt.Errorf("node %s lists %s in importedBy but is not imported by it", id, importer))
+			}
+		}
+	}
+
+	return errs
+}
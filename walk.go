@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// walkFollowingSymlinks walks the file tree like filepath.Walk but descends into
+// symlinked directories. Each real directory is visited at most once, so links
+// pointing back at an ancestor cannot cause an infinite loop.
+func walkFollowingSymlinks(root string, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	visited := make(map[string]bool)
+	err = walkFollowing(root, info, fn, visited)
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkFollowing is the recursive helper for walkFollowingSymlinks
+func walkFollowing(path string, info fs.FileInfo, fn filepath.WalkFunc, visited map[string]bool) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fn(path, info, err)
+	}
+	if visited[realPath] {
+		return nil
+	}
+	visited[realPath] = true
+
+	if err := fn(path, info, nil); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fn(path, info, err)
+	}
+
+	for _, entry := range entries {
+		childPath := filepath.Join(path, entry.Name())
+
+		// Stat follows symlinks; broken links are skipped
+		childInfo, err := os.Stat(childPath)
+		if err != nil {
+			continue
+		}
+
+		err = walkFollowing(childPath, childInfo, fn, visited)
+		if err == filepath.SkipDir {
+			if childInfo.IsDir() {
+				continue
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
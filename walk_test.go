@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFollowSymlinks(t *testing.T) {
+	base := writeFiles(t, map[string]string{
+		"app/src/App.jsx":          "import Card from './shared/Card'\nexport default function App() { return <Card /> }\n",
+		"packages/shared/Card.jsx": "export default function Card() { return <div /> }\n",
+	})
+	root := filepath.Join(base, "app")
+	if err := os.Symlink(filepath.Join(base, "packages", "shared"), filepath.Join(root, "src", "shared")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	// A link back to an ancestor must not make the walk loop forever
+	if err := os.Symlink(root, filepath.Join(root, "src", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	card := filepath.Join("src", "shared", "Card.jsx")
+	project := scanDir(t, root, ScanOptions{})
+	if _, ok := project.NodesMap[card]; ok {
+		t.Errorf("%s scanned without FollowSymlinks", card)
+	}
+
+	project = scanDir(t, root, ScanOptions{FollowSymlinks: true})
+	if _, ok := project.NodesMap[card]; !ok {
+		t.Errorf("%s not scanned with FollowSymlinks; nodes %v", card, sortedNodeIDs(project))
+	}
+	if len(project.NodesMap) != 2 {
+		t.Errorf("nodes = %v, want App.jsx and Card.jsx once each", sortedNodeIDs(project))
+	}
+}